@@ -0,0 +1,297 @@
+// Package accounttest runs an AccountService in process for tests of its
+// consumers. The server is the real gRPC API and service over an in-memory
+// SQLite store, reached over bufconn, so tests need neither Postgres nor a
+// network, and can seed accounts and inject errors and latency per method.
+package accounttest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/kvetinski/account/client"
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/adapters/repository/sqlite"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+// Target is the address to dial with Server.Dial.
+const Target = "passthrough:///accounttest"
+
+// AllMethods injects a fault into every method.
+const AllMethods = "*"
+
+const bufSize = 1024 * 1024
+
+type options struct {
+	clientOpts []client.Option
+}
+
+type Option func(*options)
+
+// WithClientOptions configures the client returned by Server.Client.
+func WithClientOptions(opts ...client.Option) Option {
+	return func(o *options) {
+		o.clientOpts = append(o.clientOpts, opts...)
+	}
+}
+
+// Fault is injected into calls of a method.
+type Fault struct {
+	// Latency delays the call, or until its context is done.
+	Latency time.Duration
+	// Err fails the call after Latency. It is a status error, or an error of
+	// the client package such as client.ErrAccountNotFound, which is sent
+	// with the details the service would send.
+	Err error
+	// Times limits the fault to the next Times calls; zero means until
+	// Reset.
+	Times int
+}
+
+// Server is an in-process AccountService. It is stopped when the test ends.
+type Server struct {
+	tb       testing.TB
+	api      *grpcapi.Server
+	listener *bufconn.Listener
+	client   *client.Client
+
+	mu     sync.Mutex
+	faults map[string]*Fault
+	calls  map[string]int
+	phones int
+}
+
+// New starts a Server with an empty store.
+func New(tb testing.TB, opts ...Option) *Server {
+	tb.Helper()
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	db, err := sqlite.Open(":memory:")
+	if err != nil {
+		tb.Fatalf("accounttest: open store: %v", err)
+	}
+	tb.Cleanup(func() { _ = db.Close() })
+	if err = sqlite.Migrate(context.Background(), db); err != nil {
+		tb.Fatalf("accounttest: migrate store: %v", err)
+	}
+	repo := sqlite.New(db)
+
+	s := &Server{
+		tb:       tb,
+		api:      grpcapi.NewServer(accountsvc.New(repo, accountsvc.WithNickRules(repo)), slog.New(slog.DiscardHandler)),
+		listener: bufconn.Listen(bufSize),
+		faults:   make(map[string]*Fault),
+		calls:    make(map[string]int),
+	}
+
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	)
+	accountv1.RegisterAccountServiceServer(srv, s.api)
+	go func() {
+		_ = srv.Serve(s.listener)
+	}()
+	tb.Cleanup(func() {
+		srv.Stop()
+		_ = s.listener.Close()
+	})
+
+	clientOpts := append([]client.Option{client.WithDialOptions(grpc.WithContextDialer(s.Dial))}, o.clientOpts...)
+	if s.client, err = client.New(Target, clientOpts...); err != nil {
+		tb.Fatalf("accounttest: create client: %v", err)
+	}
+	tb.Cleanup(func() { _ = s.client.Close() })
+
+	return s
+}
+
+// Client returns a client connected to the server.
+func (s *Server) Client() *client.Client {
+	return s.client
+}
+
+// Dial connects to the server, for clients of other packages created with
+// grpc.WithContextDialer(s.Dial) and Target.
+func (s *Server) Dial(ctx context.Context, _ string) (net.Conn, error) {
+	return s.listener.DialContext(ctx)
+}
+
+// Inject adds f to calls of method, e.g. "GetAccount", or of AllMethods,
+// replacing an earlier fault of method.
+func (s *Server) Inject(method string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults[method] = &f
+}
+
+// Reset removes all faults and call counts.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = make(map[string]*Fault)
+	s.calls = make(map[string]int)
+}
+
+// Calls returns the number of calls of method, including failed ones.
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[method]
+}
+
+// Seed describes an account to create directly in the store, bypassing
+// injected faults.
+type Seed struct {
+	// Phone is a unique +1555 number when empty.
+	Phone string
+	// Nick is generated when empty.
+	Nick string
+
+	DisplayName string
+	Bio         string
+	AvatarURL   string
+	Locale      string
+	TimeZone    string
+
+	// Status is ACTIVE when unspecified; SUSPENDED and BANNED are supported.
+	Status client.AccountStatus
+}
+
+// SeedAccount creates an account and fails the test if the service rejects
+// it.
+func (s *Server) SeedAccount(seed Seed) *client.Account {
+	s.tb.Helper()
+	ctx := context.Background()
+
+	if seed.Phone == "" {
+		s.mu.Lock()
+		s.phones++
+		seed.Phone = fmt.Sprintf("+1555%07d", s.phones)
+		s.mu.Unlock()
+	}
+
+	resp, err := s.api.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: seed.Phone, Nick: seed.Nick, Locale: seed.Locale})
+	if err != nil {
+		s.tb.Fatalf("accounttest: seed account %s: %v", seed.Phone, err)
+	}
+	id := resp.GetAccount().GetId()
+
+	update := &accountv1.UpdateProfileRequest{
+		Id:          id,
+		DisplayName: optional(seed.DisplayName),
+		Bio:         optional(seed.Bio),
+		AvatarUrl:   optional(seed.AvatarURL),
+		Locale:      optional(seed.Locale),
+		TimeZone:    optional(seed.TimeZone),
+	}
+	if resp, err = s.api.UpdateProfile(ctx, update); err != nil {
+		s.tb.Fatalf("accounttest: seed profile of %s: %v", id, err)
+	}
+
+	req := &accountv1.UpdateStatusRequest{Id: id, Reason: client.StatusReason_STATUS_REASON_OTHER}
+	switch seed.Status {
+	case client.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED, client.AccountStatus_ACCOUNT_STATUS_ACTIVE:
+	case client.AccountStatus_ACCOUNT_STATUS_SUSPENDED:
+		resp, err = s.api.SuspendAccount(ctx, req)
+	case client.AccountStatus_ACCOUNT_STATUS_BANNED:
+		resp, err = s.api.BanAccount(ctx, req)
+	default:
+		s.tb.Fatalf("accounttest: cannot seed account with status %v", seed.Status)
+	}
+	if err != nil {
+		s.tb.Fatalf("accounttest: seed status of %s: %v", id, err)
+	}
+
+	return resp.GetAccount()
+}
+
+func optional(v string) *string {
+	if v == "" {
+		return nil
+	}
+
+	return &v
+}
+
+// fault counts a call of method and returns the fault to inject, if any.
+func (s *Server) fault(fullMethod string) *Fault {
+	method := path.Base(fullMethod)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls[method]++
+	for _, key := range []string{method, AllMethods} {
+		f, ok := s.faults[key]
+		if !ok {
+			continue
+		}
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				delete(s.faults, key)
+			}
+		}
+		fault := *f
+		return &fault
+	}
+
+	return nil
+}
+
+// inject applies the fault of fullMethod.
+func (s *Server) inject(ctx context.Context, fullMethod string) error {
+	f := s.fault(fullMethod)
+	if f == nil {
+		return nil
+	}
+
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if f.Err != nil {
+		return grpcapi.StatusError(f.Err)
+	}
+
+	return nil
+}
+
+func (s *Server) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.inject(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (s *Server) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.inject(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
@@ -0,0 +1,278 @@
+// Package client is the Go SDK of the account service. It wraps the
+// generated AccountService client with deadlines, retries of idempotent
+// calls, keepalive, load balancing and tracing, and returns errors that match
+// the Err* values of this package with errors.Is.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+)
+
+const (
+	defaultTimeout       = 5 * time.Second
+	defaultLoadBalancing = "round_robin"
+)
+
+// idempotentMethods are retried when the server is unavailable. Reads are
+// safe to repeat, and UpdateProfile sets fields to the same values again.
+var idempotentMethods = []string{
+	accountv1.AccountService_GetAccount_FullMethodName,
+	accountv1.AccountService_GetAccountByNick_FullMethodName,
+	accountv1.AccountService_ListNickHistory_FullMethodName,
+	accountv1.AccountService_CheckNick_FullMethodName,
+	accountv1.AccountService_UpdateProfile_FullMethodName,
+	accountv1.AccountService_ListNickRules_FullMethodName,
+}
+
+// RetryPolicy configures retries of idempotent calls that fail with
+// Unavailable, e.g. because the server shed load. The delay before attempt n
+// is random up to min(InitialBackoff*BackoffMultiplier^(n-1), MaxBackoff).
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt; gRPC caps it at 5. Retries
+	// are disabled when it is 1 or less.
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+}
+
+// DefaultRetryPolicy is used unless WithRetryPolicy is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:       4,
+	InitialBackoff:    100 * time.Millisecond,
+	MaxBackoff:        time.Second,
+	BackoffMultiplier: 2,
+}
+
+// DefaultKeepalive pings idle connections, so that connections silently
+// dropped by load balancers are noticed before a call is sent on them.
+var DefaultKeepalive = keepalive.ClientParameters{
+	Time:                30 * time.Second,
+	Timeout:             10 * time.Second,
+	PermitWithoutStream: true,
+}
+
+type options struct {
+	timeout       time.Duration
+	retry         RetryPolicy
+	keepalive     keepalive.ClientParameters
+	loadBalancing string
+	creds         credentials.TransportCredentials
+	callerID      string
+	otelOpts      []otelgrpc.Option
+	dialOpts      []grpc.DialOption
+}
+
+type Option func(*options)
+
+// WithTimeout sets the deadline of unary calls whose context has none, or a
+// later one; 5s by default. UploadAvatar is only bound by its context.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = p
+	}
+}
+
+func WithKeepalive(p keepalive.ClientParameters) Option {
+	return func(o *options) {
+		o.keepalive = p
+	}
+}
+
+// WithLoadBalancingPolicy names the gRPC load balancing policy,
+// "round_robin" by default. It spreads calls over all addresses the target
+// resolves to, e.g. the pods behind "dns:///account-headless:9090".
+func WithLoadBalancingPolicy(name string) Option {
+	return func(o *options) {
+		o.loadBalancing = name
+	}
+}
+
+// WithTransportCredentials secures the connection; it is plaintext by
+// default.
+func WithTransportCredentials(creds credentials.TransportCredentials) Option {
+	return func(o *options) {
+		o.creds = creds
+	}
+}
+
+// WithCallerID sends id in the x-caller-id header of every call. The server
+// applies per-client rate limits and signup velocity limits to it.
+func WithCallerID(id string) Option {
+	return func(o *options) {
+		o.callerID = id
+	}
+}
+
+// WithTelemetryOptions configures the OpenTelemetry instrumentation, which
+// uses the global tracer and meter providers by default.
+func WithTelemetryOptions(opts ...otelgrpc.Option) Option {
+	return func(o *options) {
+		o.otelOpts = append(o.otelOpts, opts...)
+	}
+}
+
+// WithDialOptions adds gRPC dial options, applied after the ones of the
+// client.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOpts = append(o.dialOpts, opts...)
+	}
+}
+
+// Client is an AccountService client. Its errors can be matched with
+// errors.Is against the Err* values, and with errors.As against the typed
+// errors of this package; status.Code still returns their gRPC code.
+type Client struct {
+	accountv1.AccountServiceClient
+
+	conn *grpc.ClientConn
+}
+
+// New creates a client for target, e.g. "dns:///account:9090". It does not
+// connect until the first call.
+func New(target string, opts ...Option) (*Client, error) {
+	o := options{
+		timeout:       defaultTimeout,
+		retry:         DefaultRetryPolicy,
+		keepalive:     DefaultKeepalive,
+		loadBalancing: defaultLoadBalancing,
+		creds:         insecure.NewCredentials(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	serviceConfig, err := o.serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	unary := []grpc.UnaryClientInterceptor{unaryErrorInterceptor}
+	stream := []grpc.StreamClientInterceptor{streamErrorInterceptor}
+	if o.callerID != "" {
+		unary = append(unary, unaryCallerInterceptor(o.callerID))
+		stream = append(stream, streamCallerInterceptor(o.callerID))
+	}
+
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(o.creds),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithKeepaliveParams(o.keepalive),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(o.otelOpts...)),
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(stream...),
+	}, o.dialOpts...)
+
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("account client: %w", err)
+	}
+
+	return &Client{AccountServiceClient: accountv1.NewAccountServiceClient(conn), conn: conn}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// serviceConfig returns the gRPC service config with the load balancing
+// policy, timeouts and retry policy.
+func (o options) serviceConfig() (string, error) {
+	type methodName struct {
+		Service string `json:"service"`
+		Method  string `json:"method,omitempty"`
+	}
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name        []methodName `json:"name"`
+		Timeout     string       `json:"timeout,omitempty"`
+		RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+	}
+
+	service := accountv1.AccountService_ServiceDesc.ServiceName
+	var timeout string
+	if o.timeout > 0 {
+		timeout = seconds(o.timeout)
+	}
+
+	idempotent := methodConfig{Timeout: timeout}
+	for _, m := range idempotentMethods {
+		idempotent.Name = append(idempotent.Name, methodName{Service: service, Method: path.Base(m)})
+	}
+	if o.retry.MaxAttempts > 1 {
+		if o.retry.InitialBackoff <= 0 || o.retry.MaxBackoff <= 0 || o.retry.BackoffMultiplier <= 0 {
+			return "", fmt.Errorf("account client: retry policy needs positive backoffs and multiplier")
+		}
+		idempotent.RetryPolicy = &retryPolicy{
+			MaxAttempts:          o.retry.MaxAttempts,
+			InitialBackoff:       seconds(o.retry.InitialBackoff),
+			MaxBackoff:           seconds(o.retry.MaxBackoff),
+			BackoffMultiplier:    o.retry.BackoffMultiplier,
+			RetryableStatusCodes: []string{"UNAVAILABLE"},
+		}
+	}
+
+	config := map[string]any{
+		"loadBalancingConfig": []map[string]any{{o.loadBalancing: map[string]any{}}},
+		"methodConfig": []methodConfig{
+			idempotent,
+			{Name: []methodName{{Service: service}}, Timeout: timeout},
+			// Uploads take as long as the image takes to send.
+			{Name: []methodName{{Service: service, Method: path.Base(accountv1.AccountService_UploadAvatar_FullMethodName)}}},
+		},
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("account client: service config: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// seconds formats d as a protobuf JSON duration, e.g. "0.1s".
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+func unaryCallerInterceptor(callerID string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, grpcapi.CallerIDHeader, callerID)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func streamCallerInterceptor(callerID string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = metadata.AppendToOutgoingContext(ctx, grpcapi.CallerIDHeader, callerID)
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
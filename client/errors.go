@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/domain"
+)
+
+// Errors returned by the service, matched by the reason of their
+// google.rpc.ErrorInfo detail.
+var (
+	ErrInvalidNick         = domain.ErrInvalidNick
+	ErrInvalidPhone        = domain.ErrInvalidPhone
+	ErrNickAlreadyExists   = domain.ErrNickAlreadyExists
+	ErrNickInCooldown      = domain.ErrNickInCooldown
+	ErrPhoneAlreadyExists  = domain.ErrPhoneAlreadyExists
+	ErrPhoneTypeNotAllowed = domain.ErrPhoneTypeNotAllowed
+	ErrAccountNotFound     = domain.ErrAccountNotFound
+
+	ErrNickReserved      = domain.ErrNickReserved
+	ErrInvalidNickRule   = domain.ErrInvalidNickRule
+	ErrNickRuleExists    = domain.ErrNickRuleExists
+	ErrNickRuleNotFound  = domain.ErrNickRuleNotFound
+	ErrNickRulesDisabled = domain.ErrNickRulesDisabled
+	ErrNickNotAllowed    = domain.ErrNickNotAllowed
+
+	ErrInvalidDisplayName = domain.ErrInvalidDisplayName
+	ErrInvalidBio         = domain.ErrInvalidBio
+	ErrInvalidAvatarURL   = domain.ErrInvalidAvatarURL
+	ErrInvalidLocale      = domain.ErrInvalidLocale
+	ErrInvalidTimeZone    = domain.ErrInvalidTimeZone
+
+	ErrInvalidAvatar        = domain.ErrInvalidAvatar
+	ErrAvatarTooLarge       = domain.ErrAvatarTooLarge
+	ErrAvatarUploadDisabled = domain.ErrAvatarUploadDisabled
+
+	ErrAccountNotActive        = domain.ErrAccountNotActive
+	ErrInvalidStatusTransition = domain.ErrInvalidStatusTransition
+	ErrInvalidStatusReason     = domain.ErrInvalidStatusReason
+
+	ErrInvalidConsistencyToken = domain.ErrInvalidConsistencyToken
+
+	ErrRateLimited = domain.ErrRateLimited
+)
+
+type (
+	// NickNotAllowedError matches ErrNickNotAllowed and names the wordlist
+	// that rejected the nick.
+	NickNotAllowedError = domain.NickNotAllowedError
+	// PhoneTypeNotAllowedError matches ErrPhoneTypeNotAllowed and names the
+	// rejected type of number.
+	PhoneTypeNotAllowedError = domain.PhoneTypeNotAllowedError
+	// RateLimitedError matches ErrRateLimited and says when to retry.
+	RateLimitedError = domain.RateLimitedError
+)
+
+// Error is a gRPC error of the service. It unwraps to the Err* value or
+// typed error it was mapped from, and keeps the status for status.Code and
+// status.FromError.
+type Error struct {
+	status *status.Status
+	err    error
+}
+
+func (e *Error) Error() string {
+	return e.status.Err().Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// wrapError returns err as an *Error if the service mapped it from a domain
+// error, or else unchanged.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	mapped := grpcapi.DomainError(err)
+	if mapped == err {
+		return err
+	}
+
+	return &Error{status: status.Convert(err), err: mapped}
+}
+
+func unaryErrorInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return wrapError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+func streamErrorInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &errorClientStream{ClientStream: stream}, nil
+}
+
+// errorClientStream wraps the errors of a stream, except io.EOF which ends
+// it.
+type errorClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorClientStream) SendMsg(m any) error {
+	return wrapStreamError(s.ClientStream.SendMsg(m))
+}
+
+func (s *errorClientStream) RecvMsg(m any) error {
+	return wrapStreamError(s.ClientStream.RecvMsg(m))
+}
+
+func wrapStreamError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return wrapError(err)
+}
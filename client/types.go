@@ -0,0 +1,83 @@
+package client
+
+import "github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+
+// Messages and enums of account.v1, so that callers outside this module can
+// build requests and read responses.
+type (
+	AccountStatus             = accountv1.AccountStatus
+	StatusReason              = accountv1.StatusReason
+	NickRuleKind              = accountv1.NickRuleKind
+	NickCheckReason           = accountv1.NickCheckReason
+	PhoneType                 = accountv1.PhoneType
+	Profile                   = accountv1.Profile
+	Account                   = accountv1.Account
+	CreateAccountRequest      = accountv1.CreateAccountRequest
+	GetAccountRequest         = accountv1.GetAccountRequest
+	GetAccountByNickRequest   = accountv1.GetAccountByNickRequest
+	ListNickHistoryRequest    = accountv1.ListNickHistoryRequest
+	NickChange                = accountv1.NickChange
+	ListNickHistoryResponse   = accountv1.ListNickHistoryResponse
+	NickRule                  = accountv1.NickRule
+	ListNickRulesRequest      = accountv1.ListNickRulesRequest
+	ListNickRulesResponse     = accountv1.ListNickRulesResponse
+	AddNickRuleRequest        = accountv1.AddNickRuleRequest
+	RemoveNickRuleRequest     = accountv1.RemoveNickRuleRequest
+	CheckNickRequest          = accountv1.CheckNickRequest
+	CheckNickResponse         = accountv1.CheckNickResponse
+	UpdateNickRequest         = accountv1.UpdateNickRequest
+	DeleteAccountRequest      = accountv1.DeleteAccountRequest
+	UpdateProfileRequest      = accountv1.UpdateProfileRequest
+	UploadAvatarRequest       = accountv1.UploadAvatarRequest
+	UploadAvatarRequest_Id    = accountv1.UploadAvatarRequest_Id
+	UploadAvatarRequest_Chunk = accountv1.UploadAvatarRequest_Chunk
+	UpdateStatusRequest       = accountv1.UpdateStatusRequest
+	AccountResponse           = accountv1.AccountResponse
+
+	AccountService_UploadAvatarClient = accountv1.AccountService_UploadAvatarClient
+)
+
+const (
+	AccountStatus_ACCOUNT_STATUS_UNSPECIFIED = accountv1.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED
+	AccountStatus_ACCOUNT_STATUS_ACTIVE      = accountv1.AccountStatus_ACCOUNT_STATUS_ACTIVE
+	AccountStatus_ACCOUNT_STATUS_SUSPENDED   = accountv1.AccountStatus_ACCOUNT_STATUS_SUSPENDED
+	AccountStatus_ACCOUNT_STATUS_BANNED      = accountv1.AccountStatus_ACCOUNT_STATUS_BANNED
+	AccountStatus_ACCOUNT_STATUS_DELETED     = accountv1.AccountStatus_ACCOUNT_STATUS_DELETED
+)
+
+const (
+	StatusReason_STATUS_REASON_UNSPECIFIED     = accountv1.StatusReason_STATUS_REASON_UNSPECIFIED
+	StatusReason_STATUS_REASON_SPAM            = accountv1.StatusReason_STATUS_REASON_SPAM
+	StatusReason_STATUS_REASON_ABUSE           = accountv1.StatusReason_STATUS_REASON_ABUSE
+	StatusReason_STATUS_REASON_FRAUD           = accountv1.StatusReason_STATUS_REASON_FRAUD
+	StatusReason_STATUS_REASON_IMPERSONATION   = accountv1.StatusReason_STATUS_REASON_IMPERSONATION
+	StatusReason_STATUS_REASON_TERMS_VIOLATION = accountv1.StatusReason_STATUS_REASON_TERMS_VIOLATION
+	StatusReason_STATUS_REASON_APPEAL_GRANTED  = accountv1.StatusReason_STATUS_REASON_APPEAL_GRANTED
+	StatusReason_STATUS_REASON_OTHER           = accountv1.StatusReason_STATUS_REASON_OTHER
+)
+
+const (
+	NickRuleKind_NICK_RULE_KIND_UNSPECIFIED = accountv1.NickRuleKind_NICK_RULE_KIND_UNSPECIFIED
+	NickRuleKind_NICK_RULE_KIND_EXACT       = accountv1.NickRuleKind_NICK_RULE_KIND_EXACT
+	NickRuleKind_NICK_RULE_KIND_PREFIX      = accountv1.NickRuleKind_NICK_RULE_KIND_PREFIX
+	NickRuleKind_NICK_RULE_KIND_PATTERN     = accountv1.NickRuleKind_NICK_RULE_KIND_PATTERN
+)
+
+const (
+	NickCheckReason_NICK_CHECK_REASON_UNSPECIFIED    = accountv1.NickCheckReason_NICK_CHECK_REASON_UNSPECIFIED
+	NickCheckReason_NICK_CHECK_REASON_INVALID_FORMAT = accountv1.NickCheckReason_NICK_CHECK_REASON_INVALID_FORMAT
+	NickCheckReason_NICK_CHECK_REASON_RESERVED       = accountv1.NickCheckReason_NICK_CHECK_REASON_RESERVED
+	NickCheckReason_NICK_CHECK_REASON_NOT_ALLOWED    = accountv1.NickCheckReason_NICK_CHECK_REASON_NOT_ALLOWED
+	NickCheckReason_NICK_CHECK_REASON_TAKEN          = accountv1.NickCheckReason_NICK_CHECK_REASON_TAKEN
+	NickCheckReason_NICK_CHECK_REASON_COOLDOWN       = accountv1.NickCheckReason_NICK_CHECK_REASON_COOLDOWN
+)
+
+const (
+	PhoneType_PHONE_TYPE_UNSPECIFIED  = accountv1.PhoneType_PHONE_TYPE_UNSPECIFIED
+	PhoneType_PHONE_TYPE_UNKNOWN      = accountv1.PhoneType_PHONE_TYPE_UNKNOWN
+	PhoneType_PHONE_TYPE_MOBILE       = accountv1.PhoneType_PHONE_TYPE_MOBILE
+	PhoneType_PHONE_TYPE_FIXED_LINE   = accountv1.PhoneType_PHONE_TYPE_FIXED_LINE
+	PhoneType_PHONE_TYPE_VOIP         = accountv1.PhoneType_PHONE_TYPE_VOIP
+	PhoneType_PHONE_TYPE_PREMIUM_RATE = accountv1.PhoneType_PHONE_TYPE_PREMIUM_RATE
+	PhoneType_PHONE_TYPE_TOLL_FREE    = accountv1.PhoneType_PHONE_TYPE_TOLL_FREE
+)
@@ -1,27 +1,38 @@
 package main
 
 import (
+	"bytes"
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
 	"os"
 	"os/signal"
+	"path"
+	"strings"
 	"syscall"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/kvetinski/account/config"
+	"github.com/kvetinski/account/internal/adapters/blobstore"
+	"github.com/kvetinski/account/internal/adapters/cache"
 	"github.com/kvetinski/account/internal/adapters/grpcapi"
 	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
 	"github.com/kvetinski/account/internal/adapters/repository"
+	"github.com/kvetinski/account/internal/adapters/repository/sqlite"
+	"github.com/kvetinski/account/internal/phone"
 	accountsvc "github.com/kvetinski/account/internal/service/account"
 	"github.com/kvetinski/account/internal/telemetry"
 )
@@ -59,34 +70,209 @@ func run() error {
 		}
 	}()
 
-	db, err := sql.Open("postgres", cfg.PostgresURI)
+	metrics := telemetry.NewMetrics(nil)
+	repo, invalidations, closeDB, err := openRepository(ctx, cfg, metrics, logger)
 	if err != nil {
-		return fmt.Errorf("open db: %w", err)
+		return err
 	}
-	defer db.Close()
+	defer closeDB()
+	logger.Info("database connected", "driver", cfg.StorageDriver)
+
+	// Nick rules are read from the store directly: they are few, loaded in
+	// full on every refresh and not worth caching per account.
+	nickRules, _ := repo.(accountsvc.NickRuleStore)
+	// Velocity counters must not be cached either.
+	velocityStore, _ := repo.(accountsvc.VelocityStore)
 
-	if err = db.Ping(); err != nil {
-		return fmt.Errorf("ping db: %w", err)
+	if cfg.CacheEnabled && cfg.CacheRedisAddr != "" {
+		redisClient := redis.NewClient(&redis.Options{
+			Addr:     cfg.CacheRedisAddr,
+			Password: cfg.CacheRedisPassword,
+			DB:       cfg.CacheRedisDB,
+		})
+		defer redisClient.Close()
+
+		repo = cache.NewShared(repo, redisClient, cache.SharedConfig{
+			TTL:         cfg.CacheSharedTTL,
+			NegativeTTL: cfg.CacheNegativeTTL,
+			LockTTL:     cfg.CacheSharedLockTTL,
+			LockWait:    cfg.CacheSharedLockWait,
+		}, metrics)
+		logger.Info("shared account cache enabled", "addr", cfg.CacheRedisAddr, "ttl", cfg.CacheSharedTTL)
 	}
-	logger.Info("database connected")
 
-	metrics := telemetry.NewMetrics(nil)
-	if err = telemetry.RegisterDBPoolMetrics(db, nil); err != nil {
-		return fmt.Errorf("register db pool metrics: %w", err)
+	if cfg.CacheEnabled {
+		var invalidator cache.Invalidator
+		if invalidations != nil {
+			invalidator = invalidations
+		}
+
+		cached := cache.New(repo, cache.Config{
+			Size:        cfg.CacheSize,
+			TTL:         cfg.CacheTTL,
+			NegativeTTL: cfg.CacheNegativeTTL,
+		}, invalidator, metrics)
+		repo = cached
+
+		if invalidations != nil {
+			listenCtx, stopListening := context.WithCancel(context.Background())
+			defer stopListening()
+			go listenInvalidations(listenCtx, invalidations, cached, logger)
+		}
+		logger.Info("account cache enabled", "size", cfg.CacheSize, "ttl", cfg.CacheTTL)
+	}
+
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+
+	svcOpts := []accountsvc.Option{
+		accountsvc.WithMetrics(metrics),
+		accountsvc.WithNickHistory(accountsvc.NickHistoryConfig{
+			RedirectPeriod: cfg.NickRedirectPeriod,
+			Cooldown:       cfg.NickCooldown,
+		}),
+	}
+	if nickRules != nil {
+		svcOpts = append(svcOpts, accountsvc.WithNickRules(nickRules))
+	}
+	if cfg.NickFilterEnabled {
+		filter, err := newNickFilter(cfg)
+		if err != nil {
+			return err
+		}
+		svcOpts = append(svcOpts, accountsvc.WithNickFilter(filter))
+	}
+	nickGen, err := newNickGenerator(cfg)
+	if err != nil {
+		return err
+	}
+	svcOpts = append(svcOpts, accountsvc.WithNickGenerator(nickGen, cfg.NickGenerationMaxAttempts))
+	if cfg.PhoneDefaultRegion != "" {
+		if !phone.KnownRegion(cfg.PhoneDefaultRegion) {
+			return fmt.Errorf("unknown phone default region %q", cfg.PhoneDefaultRegion)
+		}
+		svcOpts = append(svcOpts, accountsvc.WithPhoneRegion(cfg.PhoneDefaultRegion))
+	}
+	numberingPlan, phonePolicy, err := newPhoneClassifier(cfg)
+	if err != nil {
+		return err
+	}
+	svcOpts = append(svcOpts, accountsvc.WithPhoneClassifier(numberingPlan, phonePolicy))
+	logger.Info("phone classification enabled", "prefixes", numberingPlan.Len(), "reject", phonePolicy.Reject, "flag", phonePolicy.Flag)
+	if cfg.PhoneNumberingPlanFile != "" && cfg.PhoneNumberingPlanRefreshInterval > 0 {
+		reloadCtx, stopReload := context.WithCancel(context.Background())
+		defer stopReload()
+		go reloadNumberingPlan(reloadCtx, numberingPlan, cfg.PhoneNumberingPlanFile, cfg.PhoneNumberingPlanRefreshInterval, logger)
+	}
+	velocityStoreName := "database"
+	if cfg.SignupVelocityStore == "memory" || velocityStore == nil {
+		velocityStore, velocityStoreName = accountsvc.NewMemoryVelocityStore(), "memory"
+	} else if pruner, ok := velocityStore.(velocityPruner); ok {
+		pruneCtx, stopPruning := context.WithCancel(context.Background())
+		defer stopPruning()
+		go pruneVelocity(pruneCtx, pruner, logger)
+	}
+	svcOpts = append(svcOpts, accountsvc.WithSignupVelocity(velocityStore, accountsvc.SignupVelocityConfig{
+		PhonePrefix:       accountsvc.VelocityLimit{Limit: cfg.SignupVelocityPhonePrefixLimit, Window: cfg.SignupVelocityPhonePrefixWindow},
+		PhonePrefixDigits: cfg.SignupVelocityPhonePrefixDigits,
+		Caller:            accountsvc.VelocityLimit{Limit: cfg.SignupVelocityCallerLimit, Window: cfg.SignupVelocityCallerWindow},
+		IP:                accountsvc.VelocityLimit{Limit: cfg.SignupVelocityIPLimit, Window: cfg.SignupVelocityIPWindow},
+	}))
+	logger.Info("signup velocity limits enabled", "store", velocityStoreName)
+	trustedProxies, err := parsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("parse TRUSTED_PROXIES: %w", err)
+	}
+	if cfg.AvatarDir != "" {
+		// Avatar URLs must be https, see UpdateProfile, and uploads get theirs
+		// from AVATAR_BASE_URL.
+		if base, err := url.Parse(cfg.AvatarBaseURL); err != nil || base.Scheme != "https" || base.Host == "" {
+			return fmt.Errorf("AVATAR_BASE_URL must be an https URL when AVATAR_DIR is set, got %q", cfg.AvatarBaseURL)
+		}
+		avatars, err := blobstore.NewLocal(cfg.AvatarDir, cfg.AvatarBaseURL)
+		if err != nil {
+			return fmt.Errorf("open avatar store: %w", err)
+		}
+		svcOpts = append(svcOpts, accountsvc.WithAvatarStore(avatars, accountsvc.AvatarConfig{
+			MaxBytes:      int64(cfg.AvatarMaxBytes),
+			MaxDimension:  cfg.AvatarMaxDimension,
+			ThumbnailSize: cfg.AvatarThumbnailSize,
+		}))
+
+		// Serve uploads next to /metrics for the TLS proxy or CDN that
+		// AVATAR_BASE_URL points at.
+		if base, err := url.Parse(cfg.AvatarBaseURL); err == nil && base.Path != "" && base.Path != "/" {
+			metricsMux.Handle(strings.TrimSuffix(base.Path, "/")+"/", avatars.Handler())
+		}
+		logger.Info("avatar uploads enabled", "dir", cfg.AvatarDir, "base_url", cfg.AvatarBaseURL)
+	}
+
+	svc := accountsvc.New(repo, svcOpts...)
+	if nickRules != nil {
+		if err = seedNickRules(ctx, svc, cfg.NickRulesFile); err != nil {
+			return err
+		}
+
+		syncCtx, stopSync := context.WithCancel(context.Background())
+		defer stopSync()
+		go svc.SyncNickRules(syncCtx, cfg.NickRulesRefreshInterval, func(err error) {
+			logger.Warn("nick rules refresh failed", "error", err)
+		})
+		logger.Info("nick rules enabled", "file", cfg.NickRulesFile, "refresh_interval", cfg.NickRulesRefreshInterval)
+	}
+	grpcServerImpl := grpcapi.NewServer(svc, logger,
+		grpcapi.WithTrustedProxies(trustedProxies),
+		grpcapi.WithUploadLimits(grpcapi.UploadLimits{MaxConcurrent: cfg.AvatarMaxUploads, Timeout: cfg.AvatarUploadTimeout}))
+
+	rateLimitCfg := grpcapi.RateLimitConfig{TrustedProxies: trustedProxies}
+	if rateLimitCfg.Methods, err = grpcapi.ParseRates(cfg.RateLimitMethods); err != nil {
+		return fmt.Errorf("parse RATE_LIMIT_METHODS: %w", err)
+	}
+	if rateLimitCfg.Clients, err = grpcapi.ParseRates(cfg.RateLimitClients); err != nil {
+		return fmt.Errorf("parse RATE_LIMIT_CLIENTS: %w", err)
+	}
+	rateLimiter := grpcapi.NewRateLimiter(rateLimitCfg, metrics)
+	logger.Info("rate limits configured", "methods", cfg.RateLimitMethods, "clients", cfg.RateLimitClients)
+
+	adminAuth := grpcapi.NewAdminAuthorizer(grpcapi.AdminAuthConfig{Callers: cfg.AdminCallers, TrustedProxies: trustedProxies})
+	if len(cfg.AdminCallers) == 0 {
+		logger.Warn("no ADMIN_CALLERS configured, admin methods are rejected", "methods", grpcapi.AdminMethods)
+	} else {
+		logger.Info("admin callers configured", "callers", cfg.AdminCallers)
 	}
 
-	repo := repository.NewWithMetrics(db, metrics)
-	svc := accountsvc.New(repo)
-	grpcServerImpl := grpcapi.NewServer(svc, logger)
+	// The metrics interceptor runs first so that rejected requests are
+	// counted too. Requests over their quota are rejected before they take
+	// a concurrency slot.
+	unary := []grpc.UnaryServerInterceptor{
+		grpcapi.UnaryMetricsInterceptor(metrics, logger),
+		adminAuth.UnaryInterceptor(),
+		rateLimiter.UnaryInterceptor(),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		grpcapi.StreamMetricsInterceptor(metrics, logger),
+		rateLimiter.StreamInterceptor(),
+	}
+	if cfg.ConcurrencyLimitEnabled {
+		limiter := grpcapi.NewConcurrencyLimiter(grpcapi.ConcurrencyLimitConfig{
+			InitialLimit: cfg.ConcurrencyLimitInitial,
+			MinLimit:     cfg.ConcurrencyLimitMin,
+			MaxLimit:     cfg.ConcurrencyLimitMax,
+		}, metrics)
+		unary = append(unary, limiter.UnaryInterceptor())
+		stream = append(stream, limiter.StreamInterceptor())
+		logger.Info("adaptive concurrency limit enabled", "initial", cfg.ConcurrencyLimitInitial, "min", cfg.ConcurrencyLimitMin, "max", cfg.ConcurrencyLimitMax)
+	}
 
 	grpcSrv := grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		grpc.UnaryInterceptor(grpcapi.UnaryMetricsInterceptor(metrics, logger)),
+		// Let clients keep idle connections alive, as the client package does.
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: 10 * time.Second, PermitWithoutStream: true}),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
 	)
 	accountv1.RegisterAccountServiceServer(grpcSrv, grpcServerImpl)
 
-	metricsMux := http.NewServeMux()
-	metricsMux.Handle("/metrics", promhttp.Handler())
 	metricsSrv := &http.Server{
 		Addr:    cfg.MetricsAddr,
 		Handler: metricsMux,
@@ -157,3 +343,343 @@ func run() error {
 	logger.Info("shutdown complete")
 	return nil
 }
+
+// openRepository opens the configured storage backend. Invalidations is nil
+// for backends that cannot broadcast cache invalidations between replicas.
+func openRepository(ctx context.Context, cfg config.Config, metrics *telemetry.Metrics, logger *slog.Logger) (accountsvc.Repository, *repository.Invalidations, func(), error) {
+	switch cfg.StorageDriver {
+	case "postgres":
+		return openPostgres(ctx, cfg, metrics, logger)
+	case "sqlite":
+		repo, closeDB, err := openSQLite(ctx, cfg, metrics)
+		return repo, nil, closeDB, err
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
+
+// seedNickRules adds the rules from path, or the built-in defaults when path
+// is empty, to the store and loads them into svc.
+func seedNickRules(ctx context.Context, svc *accountsvc.Service, path string) error {
+	data := config.DefaultNickRules
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return fmt.Errorf("read nick rules: %w", err)
+		}
+	}
+
+	rules, err := accountsvc.ParseNickRules(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("parse nick rules: %w", err)
+	}
+	if err = svc.SeedNickRules(ctx, rules); err != nil {
+		return fmt.Errorf("seed nick rules: %w", err)
+	}
+
+	return nil
+}
+
+// newPhoneClassifier loads the numbering plan from PHONE_NUMBERING_PLAN_FILE,
+// or the built-in one when it is empty, and the phone type policy.
+func newPhoneClassifier(cfg config.Config) (*accountsvc.NumberingPlan, accountsvc.PhoneTypePolicy, error) {
+	var policy accountsvc.PhoneTypePolicy
+	var err error
+	if policy.Reject, err = accountsvc.ParsePhoneTypes(cfg.PhoneTypesReject); err != nil {
+		return nil, policy, fmt.Errorf("parse PHONE_TYPES_REJECT: %w", err)
+	}
+	if policy.Flag, err = accountsvc.ParsePhoneTypes(cfg.PhoneTypesFlag); err != nil {
+		return nil, policy, fmt.Errorf("parse PHONE_TYPES_FLAG: %w", err)
+	}
+
+	data := config.DefaultNumberingPlan
+	if cfg.PhoneNumberingPlanFile != "" {
+		if data, err = os.ReadFile(cfg.PhoneNumberingPlanFile); err != nil {
+			return nil, policy, fmt.Errorf("read numbering plan: %w", err)
+		}
+	}
+
+	plan, err := accountsvc.ParseNumberingPlan(bytes.NewReader(data))
+	if err != nil {
+		return nil, policy, fmt.Errorf("parse numbering plan: %w", err)
+	}
+
+	return plan, policy, nil
+}
+
+// reloadNumberingPlan rereads path every interval until ctx is done, so an
+// updated dataset takes effect without a restart. A file that fails to parse
+// keeps the previous plan.
+func reloadNumberingPlan(ctx context.Context, plan *accountsvc.NumberingPlan, path string, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var loaded time.Time
+	if info, err := os.Stat(path); err == nil {
+		loaded = info.ModTime()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Warn("numbering plan reload failed", "error", err)
+			continue
+		}
+		if info.ModTime().Equal(loaded) {
+			continue
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			logger.Warn("numbering plan reload failed", "error", err)
+			continue
+		}
+		err = plan.Load(f)
+		f.Close()
+		if err != nil {
+			logger.Warn("numbering plan reload failed", "error", err)
+			continue
+		}
+
+		loaded = info.ModTime()
+		logger.Info("numbering plan reloaded", "file", path, "prefixes", plan.Len())
+	}
+}
+
+// velocityPruner is implemented by velocity stores whose counters outlive
+// the process.
+type velocityPruner interface {
+	PruneVelocity(ctx context.Context, now time.Time) (int64, error)
+}
+
+// pruneVelocity deletes expired velocity counters every minute until ctx is
+// done.
+func pruneVelocity(ctx context.Context, pruner velocityPruner, logger *slog.Logger) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := pruner.PruneVelocity(ctx, now); err != nil {
+				logger.Warn("velocity counter pruning failed", "error", err)
+			}
+		}
+	}
+}
+
+// parsePrefixes parses CIDR prefixes such as "10.0.0.0/8"; a bare address
+// stands for itself.
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		if addr, err := netip.ParseAddr(v); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+// newNickFilter loads the wordlists from NICK_FILTER_WORDLISTS, given as
+// category=path pairs, or the built-in ones when it is empty.
+func newNickFilter(cfg config.Config) (*accountsvc.WordlistFilter, error) {
+	lists := make(map[string][]string)
+	if len(cfg.NickFilterWordlists) == 0 {
+		files, err := fs.Glob(config.NickFilterFiles, "nick_filter/*.txt")
+		if err != nil {
+			return nil, fmt.Errorf("list nick filter wordlists: %w", err)
+		}
+		for _, file := range files {
+			category := strings.TrimSuffix(path.Base(file), ".txt")
+			if category == "allow" {
+				continue
+			}
+			if lists[category], err = readWordlist(config.NickFilterFiles.ReadFile, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, entry := range cfg.NickFilterWordlists {
+		category, file, ok := strings.Cut(entry, "=")
+		if !ok || category == "" || file == "" {
+			return nil, fmt.Errorf("invalid nick filter wordlist %q, expected category=path", entry)
+		}
+
+		terms, err := readWordlist(os.ReadFile, file)
+		if err != nil {
+			return nil, err
+		}
+		lists[category] = append(lists[category], terms...)
+	}
+
+	readAllow, allowFile := config.NickFilterFiles.ReadFile, "nick_filter/allow.txt"
+	if cfg.NickFilterAllowlist != "" {
+		readAllow, allowFile = os.ReadFile, cfg.NickFilterAllowlist
+	}
+	allow, err := readWordlist(readAllow, allowFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountsvc.NewWordlistFilter(lists, allow), nil
+}
+
+// newNickGenerator returns the strategy named by NICK_GENERATOR: "words" for
+// adjective-noun-number nicks from NICK_GENERATOR_WORDS_DIR (or the built-in
+// lists), or "random".
+func newNickGenerator(cfg config.Config) (accountsvc.NickGenerator, error) {
+	switch cfg.NickGenerator {
+	case "random":
+		return accountsvc.NewRandomNickGenerator(0), nil
+	case "words":
+		fsys, err := fs.Sub(config.NickWordFiles, "nick_words")
+		if err != nil {
+			return nil, fmt.Errorf("open nick words: %w", err)
+		}
+		if cfg.NickGeneratorWordsDir != "" {
+			fsys = os.DirFS(cfg.NickGeneratorWordsDir)
+		}
+
+		words, err := accountsvc.LoadNickWords(fsys)
+		if err != nil {
+			return nil, err
+		}
+		return accountsvc.NewWordlistNickGenerator(words, cfg.NickGeneratorLocale, cfg.NickGeneratorDigits)
+	default:
+		return nil, fmt.Errorf("unknown nick generator %q, expected words or random", cfg.NickGenerator)
+	}
+}
+
+func readWordlist(readFile func(string) ([]byte, error), name string) ([]string, error) {
+	data, err := readFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read wordlist: %w", err)
+	}
+
+	terms, err := accountsvc.ParseWordlist(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse wordlist %s: %w", name, err)
+	}
+
+	return terms, nil
+}
+
+func listenInvalidations(ctx context.Context, invalidations *repository.Invalidations, handler repository.InvalidationHandler, logger *slog.Logger) {
+	for {
+		err := invalidations.Listen(ctx, handler)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("cache invalidation listener disconnected, retrying", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func openPostgres(ctx context.Context, cfg config.Config, metrics *telemetry.Metrics, logger *slog.Logger) (accountsvc.Repository, *repository.Invalidations, func(), error) {
+	poolCfg := repository.PoolConfig{
+		MaxConns:          int32(cfg.PostgresMaxConns),
+		MinConns:          int32(cfg.PostgresMinConns),
+		MaxConnLifetime:   cfg.PostgresMaxConnLifetime,
+		MaxConnIdleTime:   cfg.PostgresMaxConnIdleTime,
+		HealthCheckPeriod: cfg.PostgresHealthCheckPeriod,
+	}
+
+	var pools []*pgxpool.Pool
+	closePools := func() {
+		for _, pool := range pools {
+			pool.Close()
+		}
+	}
+
+	primary, err := repository.NewPool(ctx, cfg.PostgresURI, poolCfg)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("open postgres: %w", err)
+	}
+	pools = append(pools, primary)
+
+	if err = telemetry.RegisterPgxPoolMetrics(primary, telemetry.DBRolePrimary, "primary", nil); err != nil {
+		closePools()
+		return nil, nil, nil, fmt.Errorf("register db pool metrics: %w", err)
+	}
+
+	// Replicas connect lazily: one that is down at startup leaves the
+	// rotation until it recovers instead of keeping the service down.
+	var replicas []*pgxpool.Pool
+	for i, uri := range cfg.PostgresReplicaURIs {
+		replica, err := repository.OpenPool(ctx, uri, poolCfg)
+		if err != nil {
+			closePools()
+			return nil, nil, nil, fmt.Errorf("open postgres replica %d: %w", i, err)
+		}
+		pools = append(pools, replica)
+		replicas = append(replicas, replica)
+
+		if err = telemetry.RegisterPgxPoolMetrics(replica, telemetry.DBRoleReplica, fmt.Sprintf("replica-%d", i), nil); err != nil {
+			closePools()
+			return nil, nil, nil, fmt.Errorf("register db pool metrics: %w", err)
+		}
+	}
+
+	repo := repository.NewWithReplicas(primary, repository.ReplicaConfig{
+		Pools:   replicas,
+		MaxWait: cfg.PostgresReplicaMaxWait,
+	}, metrics)
+	for i, err := range repo.CheckReplicas(ctx, cfg.PostgresReplicaCheckInterval) {
+		if err != nil {
+			logger.Warn("postgres replica unavailable, reading from the other replicas or the primary until it recovers", "replica", i, "error", err)
+		}
+	}
+
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	go repo.MonitorReplicas(monitorCtx, cfg.PostgresReplicaCheckInterval)
+
+	return repo, repository.NewInvalidations(primary, metrics), func() {
+		stopMonitor()
+		closePools()
+	}, nil
+}
+
+func openSQLite(ctx context.Context, cfg config.Config, metrics *telemetry.Metrics) (accountsvc.Repository, func(), error) {
+	db, err := sqlite.Open(cfg.SQLitePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open db: %w", err)
+	}
+	closeDB := func() { _ = db.Close() }
+
+	if err = db.PingContext(ctx); err != nil {
+		closeDB()
+		return nil, nil, fmt.Errorf("ping db: %w", err)
+	}
+
+	if err = telemetry.RegisterDBPoolMetrics(db, nil); err != nil {
+		closeDB()
+		return nil, nil, fmt.Errorf("register db pool metrics: %w", err)
+	}
+
+	if err = sqlite.Migrate(ctx, db); err != nil {
+		closeDB()
+		return nil, nil, fmt.Errorf("migrate sqlite: %w", err)
+	}
+
+	return sqlite.NewWithMetrics(db, metrics), closeDB, nil
+}
@@ -0,0 +1,10 @@
+package config
+
+import "embed"
+
+// NickFilterFiles holds the default nick filter wordlists, used when
+// NICK_FILTER_WORDLISTS is not set: nick_filter/<category>.txt, plus
+// nick_filter/allow.txt, the default for NICK_FILTER_ALLOWLIST.
+//
+//go:embed nick_filter/*.txt
+var NickFilterFiles embed.FS
@@ -0,0 +1,9 @@
+package config
+
+import _ "embed"
+
+// DefaultNickRules is the seed list used when NICK_RULES_FILE is not set, in
+// the format read by accountsvc.ParseNickRules.
+//
+//go:embed nick_rules.txt
+var DefaultNickRules []byte
@@ -0,0 +1,10 @@
+package config
+
+import "embed"
+
+// NickWordFiles holds the default wordlists for generated nicks, used when
+// NICK_GENERATOR_WORDS_DIR is not set: nick_words/<locale>/adjectives.txt and
+// nick_words/<locale>/nouns.txt.
+//
+//go:embed nick_words
+var NickWordFiles embed.FS
@@ -0,0 +1,10 @@
+package config
+
+import _ "embed"
+
+// DefaultNumberingPlan is the phone numbering plan used when
+// PHONE_NUMBERING_PLAN_FILE is not set, in the format read by
+// accountsvc.ParseNumberingPlan.
+//
+//go:embed numbering_plan.txt
+var DefaultNumberingPlan []byte
@@ -0,0 +1,141 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+// Local stores blobs as files under a directory and builds their URLs from a
+// base URL the directory is served from, e.g. by Handler. It suits a single
+// instance or a shared volume; an object store such as S3 can implement
+// accountsvc.BlobStore the same way.
+type Local struct {
+	dir     string
+	baseURL *url.URL
+}
+
+var _ accountsvc.BlobStore = (*Local)(nil)
+
+func NewLocal(dir, baseURL string) (*Local, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse blob base url: %w", err)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create blob dir: %w", err)
+	}
+
+	return &Local{dir: dir, baseURL: base}, nil
+}
+
+// Put writes data to a temporary file and renames it into place, so readers
+// never see a partially written blob.
+func (l *Local) Put(_ context.Context, key, _ string, data []byte) (string, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return "", fmt.Errorf("create blob dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("write blob: %w", err)
+	}
+	if err = tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("chmod blob: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return "", fmt.Errorf("close blob: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), name); err != nil {
+		return "", fmt.Errorf("rename blob: %w", err)
+	}
+
+	return l.baseURL.JoinPath(key).String(), nil
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete blob: %w", err)
+	}
+
+	return nil
+}
+
+// Key returns the key of a URL returned by Put.
+func (l *Local) Key(rawURL string) (string, bool) {
+	key, ok := strings.CutPrefix(rawURL, l.baseURL.String())
+	if !ok {
+		return "", false
+	}
+	if _, err := l.path(key); err != nil {
+		return "", false
+	}
+
+	return key, true
+}
+
+// Handler serves the stored blobs. Mount it at the path of the base URL.
+// Directories are not listed, so the account IDs under it stay private.
+func (l *Local) Handler() http.Handler {
+	return http.StripPrefix(strings.TrimSuffix(l.baseURL.Path, "/"), http.FileServer(filesOnly{http.Dir(l.dir)}))
+}
+
+// filesOnly hides the directories of a file system, so that http.FileServer
+// answers 404 instead of listing them.
+type filesOnly struct {
+	fs http.FileSystem
+}
+
+func (f filesOnly) Open(name string) (http.File, error) {
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return nil, fs.ErrNotExist
+	}
+
+	return file, nil
+}
+
+// path maps key to a file under dir, rejecting keys that would escape it.
+func (l *Local) path(key string) (string, error) {
+	if key == "" || !fs.ValidPath(key) || path.Clean(key) != key {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
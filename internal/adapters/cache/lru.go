@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+type entry struct {
+	id       uuid.UUID
+	account  domain.Account
+	notFound bool
+	expires  time.Time
+}
+
+// tombstoneTTL is how long an invalidated id is remembered. Fills that take
+// longer are not cached, since their tombstone may already be gone.
+const tombstoneTTL = 10 * time.Second
+
+// lru is a fixed-size, least-recently-used map of accounts whose entries also
+// expire after their TTL. A delete leaves a short-lived tombstone for its id
+// and a purge one for the whole cache, so a fill that started before an
+// invalidation of the same id can be dropped instead of caching the stale
+// value it read, while fills of other ids are kept.
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[uuid.UUID]*list.Element
+	// seq counts invalidations; tombstones and purged hold the seq of the
+	// latest invalidation of an id and of the whole cache.
+	seq        uint64
+	purged     uint64
+	tombstones map[uuid.UUID]uint64
+	// expiry lists tombstones in the order they were written.
+	expiry []tombstone
+}
+
+type tombstone struct {
+	id      uuid.UUID
+	seq     uint64
+	created time.Time
+}
+
+// fill marks the start of a read whose result may be cached.
+type fill struct {
+	seq     uint64
+	started time.Time
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:       size,
+		order:      list.New(),
+		items:      make(map[uuid.UUID]*list.Element, size),
+		tombstones: make(map[uuid.UUID]uint64),
+	}
+}
+
+func (c *lru) get(id uuid.UUID, now time.Time) (entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[id]
+	if !ok {
+		return entry{}, false
+	}
+
+	e := el.Value.(entry)
+	if !now.Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.items, id)
+		return entry{}, false
+	}
+
+	c.order.MoveToFront(el)
+	return e, true
+}
+
+func (c *lru) begin(now time.Time) fill {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return fill{seq: c.seq, started: now}
+}
+
+// set stores e unless e.id was invalidated after f started.
+func (c *lru) set(e entry, f fill, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(f.started) >= tombstoneTTL || f.seq < c.purged {
+		return
+	}
+	if seq, ok := c.tombstones[e.id]; ok && f.seq < seq {
+		return
+	}
+
+	if el, ok := c.items[e.id]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[e.id] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(entry).id)
+	}
+}
+
+func (c *lru) delete(id uuid.UUID, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(now)
+	c.seq++
+	c.tombstones[id] = c.seq
+	c.expiry = append(c.expiry, tombstone{id: id, seq: c.seq, created: now})
+	if el, ok := c.items[id]; ok {
+		c.order.Remove(el)
+		delete(c.items, id)
+	}
+}
+
+func (c *lru) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	c.purged = c.seq
+	c.order.Init()
+	clear(c.items)
+	clear(c.tombstones)
+	c.expiry = nil
+}
+
+// expire drops tombstones older than tombstoneTTL.
+func (c *lru) expire(now time.Time) {
+	n := 0
+	for ; n < len(c.expiry) && now.Sub(c.expiry[n].created) >= tombstoneTTL; n++ {
+		if t := c.expiry[n]; c.tombstones[t.id] == t.seq {
+			delete(c.tombstones, t.id)
+		}
+	}
+	c.expiry = c.expiry[n:]
+}
+
+func (c *lru) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
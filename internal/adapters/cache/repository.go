@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+// Invalidator tells other service replicas to drop an account from their
+// caches after a local mutation.
+type Invalidator interface {
+	Publish(ctx context.Context, id uuid.UUID) error
+}
+
+type Config struct {
+	Size        int
+	TTL         time.Duration
+	NegativeTTL time.Duration
+}
+
+// Repository is a cache-aside decorator for accountsvc.Repository. GetByID
+// results, including not-found, are kept in an in-process LRU; mutations
+// evict the account locally and publish an invalidation for other replicas.
+type Repository struct {
+	next        accountsvc.Repository
+	entries     *lru
+	ttl         time.Duration
+	negativeTTL time.Duration
+	invalidator Invalidator
+	metrics     *telemetry.Metrics
+}
+
+var _ accountsvc.Repository = (*Repository)(nil)
+
+func New(next accountsvc.Repository, cfg Config, invalidator Invalidator, metrics *telemetry.Metrics) *Repository {
+	return &Repository{
+		next:        next,
+		entries:     newLRU(cfg.Size),
+		ttl:         cfg.TTL,
+		negativeTTL: cfg.NegativeTTL,
+		invalidator: invalidator,
+		metrics:     metrics,
+	}
+}
+
+func (r *Repository) Create(ctx context.Context, id uuid.UUID, nick string, phone domain.Phone, cooldown time.Duration) (domain.Account, error) {
+	acc, err := r.next.Create(ctx, id, nick, phone, cooldown)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+
+	return acc, err
+}
+
+// GetByID serves id from the cache when possible. Reads that carry a
+// consistency token bypass the lookup, since another replica's invalidation
+// for the write behind that token may still be in flight, but still refresh
+// the cache with what they read.
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (domain.Account, error) {
+	if domain.ConsistencyTokenFrom(ctx) == "" {
+		if e, ok := r.entries.get(id, time.Now()); ok {
+			if e.notFound {
+				r.metrics.ObserveCache("local", "negative_hit")
+				return domain.Account{}, domain.ErrAccountNotFound
+			}
+
+			r.metrics.ObserveCache("local", "hit")
+			return e.account, nil
+		}
+	}
+	r.metrics.ObserveCache("local", "miss")
+
+	f := r.entries.begin(time.Now())
+	acc, err := r.next.GetByID(ctx, id)
+	now := time.Now()
+	switch {
+	case err == nil && r.ttl > 0:
+		r.entries.set(entry{id: id, account: acc, expires: now.Add(r.ttl)}, f, now)
+	case errors.Is(err, domain.ErrAccountNotFound) && r.negativeTTL > 0:
+		r.entries.set(entry{id: id, notFound: true, expires: now.Add(r.negativeTTL)}, f, now)
+	}
+
+	return acc, err
+}
+
+// GetByNick is not cached: nicks change hands, and entries keyed by nick
+// could not be invalidated by mutations that only know the account id.
+func (r *Repository) GetByNick(ctx context.Context, nick string, redirectPeriod time.Duration) (domain.Account, error) {
+	return r.next.GetByNick(ctx, nick, redirectPeriod)
+}
+
+func (r *Repository) ListNickHistory(ctx context.Context, id uuid.UUID) ([]domain.NickChange, error) {
+	return r.next.ListNickHistory(ctx, id)
+}
+
+func (r *Repository) CheckNicks(ctx context.Context, id uuid.UUID, nicks []string, cooldown time.Duration) (map[string]domain.NickCheckReason, error) {
+	return r.next.CheckNicks(ctx, id, nicks, cooldown)
+}
+
+func (r *Repository) UpdateNick(ctx context.Context, id uuid.UUID, nick string, cooldown time.Duration) (domain.Account, error) {
+	acc, err := r.next.UpdateNick(ctx, id, nick, cooldown)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+
+	return acc, err
+}
+
+func (r *Repository) UpdateProfile(ctx context.Context, id uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
+	acc, err := r.next.UpdateProfile(ctx, id, update)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+
+	return acc, err
+}
+
+func (r *Repository) UpdateStatus(ctx context.Context, id uuid.UUID, to domain.Status, reason domain.StatusReason, from ...domain.Status) (domain.Account, error) {
+	acc, err := r.next.UpdateStatus(ctx, id, to, reason, from...)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+
+	return acc, err
+}
+
+func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
+	err := r.next.Delete(ctx, id)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+
+	return err
+}
+
+func (r *Repository) ConsistencyToken(ctx context.Context) (string, error) {
+	return r.next.ConsistencyToken(ctx)
+}
+
+// Invalidate drops id from the local cache. It is called for invalidations
+// published by other replicas.
+func (r *Repository) Invalidate(id uuid.UUID) {
+	r.entries.delete(id, time.Now())
+	r.metrics.IncCacheInvalidation("remote")
+}
+
+// Purge drops every cached account, e.g. after invalidations may have been
+// missed while the listener was disconnected.
+func (r *Repository) Purge() {
+	r.entries.purge()
+	r.metrics.IncCacheInvalidation("purge")
+}
+
+// Len returns the number of cached entries.
+func (r *Repository) Len() int {
+	return r.entries.len()
+}
+
+func (r *Repository) invalidate(ctx context.Context, id uuid.UUID) {
+	r.entries.delete(id, time.Now())
+	r.metrics.IncCacheInvalidation("local")
+
+	if r.invalidator == nil {
+		return
+	}
+
+	// The mutation is committed; a failed publish leaves other replicas
+	// stale until their TTL expires, which is the cache's bound anyway.
+	_ = r.invalidator.Publish(ctx, id)
+}
@@ -0,0 +1,341 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+// keyPrefix is versioned so a change to the serialized form of
+// domain.Account never reads entries written by an older release.
+const keyPrefix = "account:v5:"
+
+const lockPollInterval = 10 * time.Millisecond
+
+// releaseLock deletes a fill lock only if this caller still holds it.
+var releaseLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// fillEntry stores an entry only while the generation of its account is the
+// one read before the repository. Invalidations replace the generation, so a
+// fill that read the row before a concurrent write cannot restore it after
+// the write deleted the entry.
+var fillEntry = redis.NewScript(`
+if (redis.call("GET", KEYS[2]) or "") ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
+type SharedConfig struct {
+	TTL         time.Duration
+	NegativeTTL time.Duration
+	// LockTTL bounds how long one caller may hold the fill lock for a key.
+	LockTTL time.Duration
+	// LockWait is how long callers that lost the fill lock wait for the
+	// winner's value before reading the repository themselves.
+	LockWait time.Duration
+}
+
+type sharedEntry struct {
+	Account  *domain.Account `json:"account,omitempty"`
+	NotFound bool            `json:"not_found,omitempty"`
+}
+
+func (e sharedEntry) result() (domain.Account, error) {
+	if e.NotFound {
+		return domain.Account{}, domain.ErrAccountNotFound
+	}
+
+	return *e.Account, nil
+}
+
+// Shared is a cache-aside decorator for accountsvc.Repository backed by a
+// Redis-compatible server shared by all service replicas. On a miss only one
+// caller per key reloads from the repository; the rest wait for its value.
+// Redis failures degrade to reading the repository.
+type Shared struct {
+	next    accountsvc.Repository
+	client  redis.Cmdable
+	cfg     SharedConfig
+	metrics *telemetry.Metrics
+}
+
+var _ accountsvc.Repository = (*Shared)(nil)
+
+func NewShared(next accountsvc.Repository, client redis.Cmdable, cfg SharedConfig, metrics *telemetry.Metrics) *Shared {
+	return &Shared{
+		next:    next,
+		client:  client,
+		cfg:     cfg,
+		metrics: metrics,
+	}
+}
+
+func (s *Shared) Create(ctx context.Context, id uuid.UUID, nick string, phone domain.Phone, cooldown time.Duration) (domain.Account, error) {
+	acc, err := s.next.Create(ctx, id, nick, phone, cooldown)
+	if err == nil {
+		s.invalidate(ctx, id)
+	}
+
+	return acc, err
+}
+
+func (s *Shared) GetByID(ctx context.Context, id uuid.UUID) (domain.Account, error) {
+	if domain.ConsistencyTokenFrom(ctx) != "" {
+		s.metrics.ObserveCache("shared", "miss")
+		return s.load(ctx, id)
+	}
+
+	key := idKey(id)
+	if e, ok := s.lookup(ctx, key); ok {
+		return e.result()
+	}
+	s.metrics.ObserveCache("shared", "miss")
+
+	lockToken := uuid.NewString()
+	locked, err := s.client.SetNX(ctx, lockKey(key), lockToken, s.cfg.LockTTL).Result()
+	if err != nil {
+		s.metrics.ObserveCache("shared", "error")
+		return s.next.GetByID(ctx, id)
+	}
+
+	if !locked {
+		if e, ok := s.awaitFill(ctx, key); ok {
+			return e.result()
+		}
+
+		return s.next.GetByID(ctx, id)
+	}
+	defer releaseLock.Run(context.WithoutCancel(ctx), s.client, []string{lockKey(key)}, lockToken)
+
+	return s.load(ctx, id)
+}
+
+// GetByNick caches which account holds a nick rather than the account
+// itself. A hit is served through GetByID, and only if that account still
+// holds the nick, so mutations need not know which nicks they affect.
+// Redirects from released nicks and misses are always read from the
+// repository.
+func (s *Shared) GetByNick(ctx context.Context, nick string, redirectPeriod time.Duration) (domain.Account, error) {
+	if domain.ConsistencyTokenFrom(ctx) != "" {
+		return s.next.GetByNick(ctx, nick, redirectPeriod)
+	}
+
+	key := nickKey(nick)
+	raw, err := s.client.Get(ctx, key).Result()
+	switch {
+	case err == nil:
+		if id, parseErr := uuid.Parse(raw); parseErr == nil {
+			if acc, getErr := s.GetByID(ctx, id); getErr == nil && strings.EqualFold(acc.Nick, nick) {
+				return acc, nil
+			}
+		}
+	case !errors.Is(err, redis.Nil):
+		s.metrics.ObserveCache("shared", "error")
+	}
+	s.metrics.ObserveCache("shared", "miss")
+
+	acc, err := s.next.GetByNick(ctx, nick, redirectPeriod)
+	if err == nil && s.cfg.TTL > 0 && strings.EqualFold(acc.Nick, nick) {
+		if setErr := s.client.Set(ctx, key, acc.ID.String(), jitter(s.cfg.TTL)).Err(); setErr != nil {
+			s.metrics.ObserveCache("shared", "error")
+		}
+	}
+
+	return acc, err
+}
+
+func (s *Shared) ListNickHistory(ctx context.Context, id uuid.UUID) ([]domain.NickChange, error) {
+	return s.next.ListNickHistory(ctx, id)
+}
+
+func (s *Shared) CheckNicks(ctx context.Context, id uuid.UUID, nicks []string, cooldown time.Duration) (map[string]domain.NickCheckReason, error) {
+	return s.next.CheckNicks(ctx, id, nicks, cooldown)
+}
+
+func (s *Shared) UpdateNick(ctx context.Context, id uuid.UUID, nick string, cooldown time.Duration) (domain.Account, error) {
+	acc, err := s.next.UpdateNick(ctx, id, nick, cooldown)
+	if err == nil {
+		s.invalidate(ctx, id)
+	}
+
+	return acc, err
+}
+
+func (s *Shared) UpdateProfile(ctx context.Context, id uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
+	acc, err := s.next.UpdateProfile(ctx, id, update)
+	if err == nil {
+		s.invalidate(ctx, id)
+	}
+
+	return acc, err
+}
+
+func (s *Shared) UpdateStatus(ctx context.Context, id uuid.UUID, to domain.Status, reason domain.StatusReason, from ...domain.Status) (domain.Account, error) {
+	acc, err := s.next.UpdateStatus(ctx, id, to, reason, from...)
+	if err == nil {
+		s.invalidate(ctx, id)
+	}
+
+	return acc, err
+}
+
+func (s *Shared) Delete(ctx context.Context, id uuid.UUID) error {
+	err := s.next.Delete(ctx, id)
+	if err == nil {
+		s.invalidate(ctx, id)
+	}
+
+	return err
+}
+
+func (s *Shared) ConsistencyToken(ctx context.Context) (string, error) {
+	return s.next.ConsistencyToken(ctx)
+}
+
+// lookup returns the cached entry for key; ok is false on a miss or when
+// Redis is unavailable.
+func (s *Shared) lookup(ctx context.Context, key string) (sharedEntry, bool) {
+	raw, err := s.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			s.metrics.ObserveCache("shared", "error")
+		}
+		return sharedEntry{}, false
+	}
+
+	var e sharedEntry
+	if err = json.Unmarshal(raw, &e); err != nil || (e.Account == nil && !e.NotFound) {
+		s.metrics.ObserveCache("shared", "error")
+		return sharedEntry{}, false
+	}
+
+	if e.NotFound {
+		s.metrics.ObserveCache("shared", "negative_hit")
+	} else {
+		s.metrics.ObserveCache("shared", "hit")
+	}
+
+	return e, true
+}
+
+// awaitFill polls key until the lock holder stores a value, for at most
+// LockWait.
+func (s *Shared) awaitFill(ctx context.Context, key string) (sharedEntry, bool) {
+	deadline := time.Now().Add(s.cfg.LockWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return sharedEntry{}, false
+		case <-time.After(lockPollInterval):
+		}
+
+		if n, err := s.client.Exists(ctx, key).Result(); err == nil && n > 0 {
+			return s.lookup(ctx, key)
+		}
+	}
+
+	return sharedEntry{}, false
+}
+
+// load reads id from the repository and stores the result, including
+// not-found, with a jittered TTL so hot keys do not expire in lockstep. The
+// entry is only stored if no invalidation happened during the read.
+func (s *Shared) load(ctx context.Context, id uuid.UUID) (domain.Account, error) {
+	gen, genErr := s.client.Get(ctx, genKey(id)).Result()
+	if errors.Is(genErr, redis.Nil) {
+		gen, genErr = "", nil
+	}
+
+	acc, err := s.next.GetByID(ctx, id)
+
+	var (
+		e   sharedEntry
+		ttl time.Duration
+	)
+	switch {
+	case err == nil:
+		e, ttl = sharedEntry{Account: &acc}, s.cfg.TTL
+	case errors.Is(err, domain.ErrAccountNotFound):
+		e, ttl = sharedEntry{NotFound: true}, s.cfg.NegativeTTL
+	default:
+		return acc, err
+	}
+
+	if genErr != nil {
+		s.metrics.ObserveCache("shared", "error")
+		return acc, err
+	}
+	if ttl > 0 {
+		if raw, marshalErr := json.Marshal(e); marshalErr == nil {
+			keys := []string{idKey(id), genKey(id)}
+			if fillErr := fillEntry.Run(ctx, s.client, keys, gen, raw, jitter(ttl).Milliseconds()).Err(); fillErr != nil {
+				s.metrics.ObserveCache("shared", "error")
+			}
+		}
+	}
+
+	return acc, err
+}
+
+// invalidate deletes the entry of id and replaces its generation, which
+// fails fills that started before. The generation outlives any such fill.
+func (s *Shared) invalidate(ctx context.Context, id uuid.UUID) {
+	genTTL := max(s.cfg.TTL, s.cfg.NegativeTTL, s.cfg.LockTTL, time.Minute)
+	_, err := s.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		p.Set(ctx, genKey(id), uuid.NewString(), genTTL)
+		p.Del(ctx, idKey(id))
+		return nil
+	})
+	if err != nil {
+		s.metrics.ObserveCache("shared", "error")
+		return
+	}
+
+	s.metrics.IncCacheInvalidation("shared")
+}
+
+// The keys of one account share a hash tag, so that fillEntry can use them
+// together on Redis Cluster.
+func idKey(id uuid.UUID) string {
+	return fmt.Sprintf("%sid:{%s}", keyPrefix, id)
+}
+
+func genKey(id uuid.UUID) string {
+	return fmt.Sprintf("%sgen:{%s}", keyPrefix, id)
+}
+
+func nickKey(nick string) string {
+	return keyPrefix + "nick:" + strings.ToLower(nick)
+}
+
+func lockKey(key string) string {
+	return key + ":lock"
+}
+
+// jitter spreads ttl by up to ±10%.
+func jitter(ttl time.Duration) time.Duration {
+	spread := int64(ttl / 5)
+	if spread <= 0 {
+		return ttl
+	}
+
+	return ttl - time.Duration(spread/2) + time.Duration(rand.Int64N(spread))
+}
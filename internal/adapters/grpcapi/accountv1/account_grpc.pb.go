@@ -20,10 +20,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AccountService_CreateAccount_FullMethodName = "/account.v1.AccountService/CreateAccount"
-	AccountService_GetAccount_FullMethodName    = "/account.v1.AccountService/GetAccount"
-	AccountService_UpdateNick_FullMethodName    = "/account.v1.AccountService/UpdateNick"
-	AccountService_DeleteAccount_FullMethodName = "/account.v1.AccountService/DeleteAccount"
+	AccountService_CreateAccount_FullMethodName    = "/account.v1.AccountService/CreateAccount"
+	AccountService_GetAccount_FullMethodName       = "/account.v1.AccountService/GetAccount"
+	AccountService_GetAccountByNick_FullMethodName = "/account.v1.AccountService/GetAccountByNick"
+	AccountService_ListNickHistory_FullMethodName  = "/account.v1.AccountService/ListNickHistory"
+	AccountService_CheckNick_FullMethodName        = "/account.v1.AccountService/CheckNick"
+	AccountService_UpdateNick_FullMethodName       = "/account.v1.AccountService/UpdateNick"
+	AccountService_UpdateProfile_FullMethodName    = "/account.v1.AccountService/UpdateProfile"
+	AccountService_UploadAvatar_FullMethodName     = "/account.v1.AccountService/UploadAvatar"
+	AccountService_DeleteAccount_FullMethodName    = "/account.v1.AccountService/DeleteAccount"
+	AccountService_SuspendAccount_FullMethodName   = "/account.v1.AccountService/SuspendAccount"
+	AccountService_ReinstateAccount_FullMethodName = "/account.v1.AccountService/ReinstateAccount"
+	AccountService_BanAccount_FullMethodName       = "/account.v1.AccountService/BanAccount"
+	AccountService_ListNickRules_FullMethodName    = "/account.v1.AccountService/ListNickRules"
+	AccountService_AddNickRule_FullMethodName      = "/account.v1.AccountService/AddNickRule"
+	AccountService_RemoveNickRule_FullMethodName   = "/account.v1.AccountService/RemoveNickRule"
 )
 
 // AccountServiceClient is the client API for AccountService service.
@@ -32,8 +43,31 @@ const (
 type AccountServiceClient interface {
 	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*AccountResponse, error)
 	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	// GetAccountByNick also resolves nicks the account released recently, so
+	// links to an old nick keep working for a while.
+	GetAccountByNick(ctx context.Context, in *GetAccountByNickRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	ListNickHistory(ctx context.Context, in *ListNickHistoryRequest, opts ...grpc.CallOption) (*ListNickHistoryResponse, error)
+	// CheckNick reports whether a nick can be claimed and suggests
+	// alternatives when it cannot.
+	CheckNick(ctx context.Context, in *CheckNickRequest, opts ...grpc.CallOption) (*CheckNickResponse, error)
 	UpdateNick(ctx context.Context, in *UpdateNickRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	// UploadAvatar stores the image and a thumbnail and sets the profile's
+	// avatar_url and avatar_thumbnail_url to where they are served from.
+	UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadAvatarRequest, AccountResponse], error)
 	DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// SuspendAccount moves an active account to suspended.
+	SuspendAccount(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	// ReinstateAccount moves a suspended account back to active.
+	ReinstateAccount(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	// BanAccount moves an active or suspended account to banned.
+	BanAccount(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	// ListNickRules lists the rules reserving or blocking nicks.
+	ListNickRules(ctx context.Context, in *ListNickRulesRequest, opts ...grpc.CallOption) (*ListNickRulesResponse, error)
+	// AddNickRule stops new accounts and nick changes from taking matching
+	// nicks. Accounts that already hold one keep it.
+	AddNickRule(ctx context.Context, in *AddNickRuleRequest, opts ...grpc.CallOption) (*NickRule, error)
+	RemoveNickRule(ctx context.Context, in *RemoveNickRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type accountServiceClient struct {
@@ -64,6 +98,36 @@ func (c *accountServiceClient) GetAccount(ctx context.Context, in *GetAccountReq
 	return out, nil
 }
 
+func (c *accountServiceClient) GetAccountByNick(ctx context.Context, in *GetAccountByNickRequest, opts ...grpc.CallOption) (*AccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_GetAccountByNick_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ListNickHistory(ctx context.Context, in *ListNickHistoryRequest, opts ...grpc.CallOption) (*ListNickHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNickHistoryResponse)
+	err := c.cc.Invoke(ctx, AccountService_ListNickHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) CheckNick(ctx context.Context, in *CheckNickRequest, opts ...grpc.CallOption) (*CheckNickResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckNickResponse)
+	err := c.cc.Invoke(ctx, AccountService_CheckNick_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *accountServiceClient) UpdateNick(ctx context.Context, in *UpdateNickRequest, opts ...grpc.CallOption) (*AccountResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(AccountResponse)
@@ -74,6 +138,29 @@ func (c *accountServiceClient) UpdateNick(ctx context.Context, in *UpdateNickReq
 	return out, nil
 }
 
+func (c *accountServiceClient) UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*AccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_UpdateProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadAvatarRequest, AccountResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AccountService_ServiceDesc.Streams[0], AccountService_UploadAvatar_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadAvatarRequest, AccountResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AccountService_UploadAvatarClient = grpc.ClientStreamingClient[UploadAvatarRequest, AccountResponse]
+
 func (c *accountServiceClient) DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -84,14 +171,97 @@ func (c *accountServiceClient) DeleteAccount(ctx context.Context, in *DeleteAcco
 	return out, nil
 }
 
+func (c *accountServiceClient) SuspendAccount(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*AccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_SuspendAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ReinstateAccount(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*AccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_ReinstateAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) BanAccount(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*AccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_BanAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ListNickRules(ctx context.Context, in *ListNickRulesRequest, opts ...grpc.CallOption) (*ListNickRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNickRulesResponse)
+	err := c.cc.Invoke(ctx, AccountService_ListNickRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) AddNickRule(ctx context.Context, in *AddNickRuleRequest, opts ...grpc.CallOption) (*NickRule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NickRule)
+	err := c.cc.Invoke(ctx, AccountService_AddNickRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) RemoveNickRule(ctx context.Context, in *RemoveNickRuleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, AccountService_RemoveNickRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AccountServiceServer is the server API for AccountService service.
 // All implementations must embed UnimplementedAccountServiceServer
 // for forward compatibility.
 type AccountServiceServer interface {
 	CreateAccount(context.Context, *CreateAccountRequest) (*AccountResponse, error)
 	GetAccount(context.Context, *GetAccountRequest) (*AccountResponse, error)
+	// GetAccountByNick also resolves nicks the account released recently, so
+	// links to an old nick keep working for a while.
+	GetAccountByNick(context.Context, *GetAccountByNickRequest) (*AccountResponse, error)
+	ListNickHistory(context.Context, *ListNickHistoryRequest) (*ListNickHistoryResponse, error)
+	// CheckNick reports whether a nick can be claimed and suggests
+	// alternatives when it cannot.
+	CheckNick(context.Context, *CheckNickRequest) (*CheckNickResponse, error)
 	UpdateNick(context.Context, *UpdateNickRequest) (*AccountResponse, error)
+	UpdateProfile(context.Context, *UpdateProfileRequest) (*AccountResponse, error)
+	// UploadAvatar stores the image and a thumbnail and sets the profile's
+	// avatar_url and avatar_thumbnail_url to where they are served from.
+	UploadAvatar(grpc.ClientStreamingServer[UploadAvatarRequest, AccountResponse]) error
 	DeleteAccount(context.Context, *DeleteAccountRequest) (*emptypb.Empty, error)
+	// SuspendAccount moves an active account to suspended.
+	SuspendAccount(context.Context, *UpdateStatusRequest) (*AccountResponse, error)
+	// ReinstateAccount moves a suspended account back to active.
+	ReinstateAccount(context.Context, *UpdateStatusRequest) (*AccountResponse, error)
+	// BanAccount moves an active or suspended account to banned.
+	BanAccount(context.Context, *UpdateStatusRequest) (*AccountResponse, error)
+	// ListNickRules lists the rules reserving or blocking nicks.
+	ListNickRules(context.Context, *ListNickRulesRequest) (*ListNickRulesResponse, error)
+	// AddNickRule stops new accounts and nick changes from taking matching
+	// nicks. Accounts that already hold one keep it.
+	AddNickRule(context.Context, *AddNickRuleRequest) (*NickRule, error)
+	RemoveNickRule(context.Context, *RemoveNickRuleRequest) (*emptypb.Empty, error)
 	mustEmbedUnimplementedAccountServiceServer()
 }
 
@@ -108,12 +278,45 @@ func (UnimplementedAccountServiceServer) CreateAccount(context.Context, *CreateA
 func (UnimplementedAccountServiceServer) GetAccount(context.Context, *GetAccountRequest) (*AccountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAccount not implemented")
 }
+func (UnimplementedAccountServiceServer) GetAccountByNick(context.Context, *GetAccountByNickRequest) (*AccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountByNick not implemented")
+}
+func (UnimplementedAccountServiceServer) ListNickHistory(context.Context, *ListNickHistoryRequest) (*ListNickHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNickHistory not implemented")
+}
+func (UnimplementedAccountServiceServer) CheckNick(context.Context, *CheckNickRequest) (*CheckNickResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckNick not implemented")
+}
 func (UnimplementedAccountServiceServer) UpdateNick(context.Context, *UpdateNickRequest) (*AccountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateNick not implemented")
 }
+func (UnimplementedAccountServiceServer) UpdateProfile(context.Context, *UpdateProfileRequest) (*AccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+}
+func (UnimplementedAccountServiceServer) UploadAvatar(grpc.ClientStreamingServer[UploadAvatarRequest, AccountResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAvatar not implemented")
+}
 func (UnimplementedAccountServiceServer) DeleteAccount(context.Context, *DeleteAccountRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccount not implemented")
 }
+func (UnimplementedAccountServiceServer) SuspendAccount(context.Context, *UpdateStatusRequest) (*AccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuspendAccount not implemented")
+}
+func (UnimplementedAccountServiceServer) ReinstateAccount(context.Context, *UpdateStatusRequest) (*AccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReinstateAccount not implemented")
+}
+func (UnimplementedAccountServiceServer) BanAccount(context.Context, *UpdateStatusRequest) (*AccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanAccount not implemented")
+}
+func (UnimplementedAccountServiceServer) ListNickRules(context.Context, *ListNickRulesRequest) (*ListNickRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNickRules not implemented")
+}
+func (UnimplementedAccountServiceServer) AddNickRule(context.Context, *AddNickRuleRequest) (*NickRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNickRule not implemented")
+}
+func (UnimplementedAccountServiceServer) RemoveNickRule(context.Context, *RemoveNickRuleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveNickRule not implemented")
+}
 func (UnimplementedAccountServiceServer) mustEmbedUnimplementedAccountServiceServer() {}
 func (UnimplementedAccountServiceServer) testEmbeddedByValue()                        {}
 
@@ -171,6 +374,60 @@ func _AccountService_GetAccount_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AccountService_GetAccountByNick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountByNickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).GetAccountByNick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_GetAccountByNick_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).GetAccountByNick(ctx, req.(*GetAccountByNickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ListNickHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNickHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ListNickHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_ListNickHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ListNickHistory(ctx, req.(*ListNickHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_CheckNick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckNickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).CheckNick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_CheckNick_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).CheckNick(ctx, req.(*CheckNickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AccountService_UpdateNick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateNickRequest)
 	if err := dec(in); err != nil {
@@ -189,6 +446,31 @@ func _AccountService_UpdateNick_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AccountService_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_UpdateProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).UpdateProfile(ctx, req.(*UpdateProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_UploadAvatar_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AccountServiceServer).UploadAvatar(&grpc.GenericServerStream[UploadAvatarRequest, AccountResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AccountService_UploadAvatarServer = grpc.ClientStreamingServer[UploadAvatarRequest, AccountResponse]
+
 func _AccountService_DeleteAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteAccountRequest)
 	if err := dec(in); err != nil {
@@ -207,6 +489,114 @@ func _AccountService_DeleteAccount_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AccountService_SuspendAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).SuspendAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_SuspendAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).SuspendAccount(ctx, req.(*UpdateStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ReinstateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ReinstateAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_ReinstateAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ReinstateAccount(ctx, req.(*UpdateStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_BanAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).BanAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_BanAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).BanAccount(ctx, req.(*UpdateStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ListNickRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNickRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ListNickRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_ListNickRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ListNickRules(ctx, req.(*ListNickRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_AddNickRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddNickRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).AddNickRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_AddNickRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).AddNickRule(ctx, req.(*AddNickRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_RemoveNickRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNickRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).RemoveNickRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_RemoveNickRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).RemoveNickRule(ctx, req.(*RemoveNickRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AccountService_ServiceDesc is the grpc.ServiceDesc for AccountService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -222,15 +612,61 @@ var AccountService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAccount",
 			Handler:    _AccountService_GetAccount_Handler,
 		},
+		{
+			MethodName: "GetAccountByNick",
+			Handler:    _AccountService_GetAccountByNick_Handler,
+		},
+		{
+			MethodName: "ListNickHistory",
+			Handler:    _AccountService_ListNickHistory_Handler,
+		},
+		{
+			MethodName: "CheckNick",
+			Handler:    _AccountService_CheckNick_Handler,
+		},
 		{
 			MethodName: "UpdateNick",
 			Handler:    _AccountService_UpdateNick_Handler,
 		},
+		{
+			MethodName: "UpdateProfile",
+			Handler:    _AccountService_UpdateProfile_Handler,
+		},
 		{
 			MethodName: "DeleteAccount",
 			Handler:    _AccountService_DeleteAccount_Handler,
 		},
+		{
+			MethodName: "SuspendAccount",
+			Handler:    _AccountService_SuspendAccount_Handler,
+		},
+		{
+			MethodName: "ReinstateAccount",
+			Handler:    _AccountService_ReinstateAccount_Handler,
+		},
+		{
+			MethodName: "BanAccount",
+			Handler:    _AccountService_BanAccount_Handler,
+		},
+		{
+			MethodName: "ListNickRules",
+			Handler:    _AccountService_ListNickRules_Handler,
+		},
+		{
+			MethodName: "AddNickRule",
+			Handler:    _AccountService_AddNickRule_Handler,
+		},
+		{
+			MethodName: "RemoveNickRule",
+			Handler:    _AccountService_RemoveNickRule_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadAvatar",
+			Handler:       _AccountService_UploadAvatar_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/account/v1/account.proto",
 }
@@ -0,0 +1,74 @@
+package grpcapi
+
+import (
+	"context"
+	"net/netip"
+	"path"
+	"slices"
+
+	"google.golang.org/grpc"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+// AdminMethods change the status of any account or the nick rules on behalf
+// of operators, and are only served to the callers in AdminAuthConfig. All of
+// them are unary.
+var AdminMethods = []string{
+	"SuspendAccount",
+	"ReinstateAccount",
+	"BanAccount",
+	"ListNickRules",
+	"AddNickRule",
+	"RemoveNickRule",
+}
+
+// AdminAuthConfig lists who may call AdminMethods.
+type AdminAuthConfig struct {
+	// Callers are caller IDs from a verified TLS client certificate, or
+	// CallerIDHeader passed on by a trusted proxy. With none, admin methods
+	// are rejected for every caller.
+	Callers []string
+	// TrustedProxies are passed to caller identification as with
+	// WithTrustedProxies.
+	TrustedProxies []netip.Prefix
+}
+
+// AdminAuthorizer rejects calls of AdminMethods by unauthenticated callers
+// with Unauthenticated, and by callers not in AdminAuthConfig with
+// PermissionDenied. Other methods pass through.
+type AdminAuthorizer struct {
+	cfg AdminAuthConfig
+}
+
+func NewAdminAuthorizer(cfg AdminAuthConfig) *AdminAuthorizer {
+	return &AdminAuthorizer{cfg: cfg}
+}
+
+func (a *AdminAuthorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.authorize(ctx, path.Base(info.FullMethod)); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a *AdminAuthorizer) authorize(ctx context.Context, method string) error {
+	if !slices.Contains(AdminMethods, method) {
+		return nil
+	}
+
+	// A CallerIDHeader sent directly is not authenticated: any client could
+	// claim an admin ID.
+	caller := callerFromContext(ctx, a.cfg.TrustedProxies)
+	switch {
+	case !caller.Authenticated:
+		return mapDomainError(domain.ErrUnauthenticated)
+	case !slices.Contains(a.cfg.Callers, caller.ID):
+		return mapDomainError(domain.ErrPermissionDenied)
+	}
+
+	return nil
+}
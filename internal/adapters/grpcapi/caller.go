@@ -0,0 +1,113 @@
+package grpcapi
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+// CallerIDHeader is the request metadata key clients identify themselves
+// with, e.g. the name of the calling service. The header is not
+// authenticated: any client can claim any ID. Clients authenticated with a
+// TLS certificate are identified by the certificate instead.
+const CallerIDHeader = "x-caller-id"
+
+// ForwardedForHeader carries the client address through proxies. It is only
+// trusted from the proxies given to WithTrustedProxies.
+const ForwardedForHeader = "x-forwarded-for"
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithTrustedProxies takes the caller IP from ForwardedForHeader when the
+// connection comes from one of proxies, e.g. the ingress, and trusts the
+// CallerIDHeader they pass on. Such proxies must set or strip the header.
+func WithTrustedProxies(proxies []netip.Prefix) ServerOption {
+	return func(s *Server) {
+		s.trustedProxies = proxies
+	}
+}
+
+// caller returns who sent the request in ctx.
+func (s *Server) caller(ctx context.Context) domain.Caller {
+	return callerFromContext(ctx, s.trustedProxies)
+}
+
+func callerFromContext(ctx context.Context, trustedProxies []netip.Prefix) domain.Caller {
+	var caller domain.Caller
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(CallerIDHeader); len(values) > 0 {
+		caller.ID = strings.TrimSpace(values[0])
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return caller
+	}
+	if principal := tlsPrincipal(p.AuthInfo); principal != "" {
+		caller.ID = principal
+		caller.Authenticated = true
+	}
+	if p.Addr == nil {
+		return caller
+	}
+	addrPort, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil {
+		return caller
+	}
+	addr := addrPort.Addr().Unmap()
+	caller.IP = addr.String()
+
+	if !trusted(addr, trustedProxies) {
+		return caller
+	}
+	caller.Authenticated = caller.ID != ""
+	// Walk the chain from the nearest hop and take the first address not
+	// added by a trusted proxy.
+	hops := strings.Split(strings.Join(md.Get(ForwardedForHeader), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		caller.IP = hop.Unmap().String()
+		if !trusted(hop.Unmap(), trustedProxies) {
+			break
+		}
+	}
+
+	return caller
+}
+
+// tlsPrincipal returns the first URI SAN, e.g. a SPIFFE ID, or else the
+// common name of a verified client certificate.
+func tlsPrincipal(authInfo credentials.AuthInfo) string {
+	info, ok := authInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	leaf := info.State.VerifiedChains[0][0]
+	if len(leaf.URIs) > 0 {
+		return leaf.URIs[0].String()
+	}
+
+	return leaf.Subject.CommonName
+}
+
+func trusted(addr netip.Addr, proxies []netip.Prefix) bool {
+	for _, p := range proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
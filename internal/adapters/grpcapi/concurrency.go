@@ -0,0 +1,187 @@
+package grpcapi
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+const (
+	defaultConcurrencyTolerance  = 2.0
+	defaultConcurrencyWindowSize = 50
+	// concurrencyWarmupWindows is the number of windows averaged before the
+	// long-term latency becomes a moving average.
+	concurrencyWarmupWindows = 10
+	// concurrencyLongRTTAlpha weights a new window in the long-term latency,
+	// roughly an average over the last 600 windows.
+	concurrencyLongRTTAlpha = 2.0 / 601
+	concurrencySmoothing    = 0.2
+)
+
+// ConcurrencyLimitConfig bounds the adaptive concurrency limit.
+type ConcurrencyLimitConfig struct {
+	InitialLimit int
+	MinLimit     int
+	MaxLimit     int
+	// Tolerance is how many times slower than the long-term average requests
+	// may get before the limit shrinks, 2 when zero.
+	Tolerance float64
+	// WindowSize is the number of completed requests each limit update is
+	// based on, 50 when zero.
+	WindowSize int
+}
+
+// ConcurrencyLimiter caps the number of requests in flight and sheds the
+// excess with Unavailable, so that a slow database builds up a short queue of
+// rejected requests instead of a long one of waiting requests.
+//
+// The limit follows a latency gradient: while requests take about as long as
+// they usually do, it grows in proportion to its square root every window;
+// once they take more than Tolerance times longer, it shrinks towards half.
+type ConcurrencyLimiter struct {
+	cfg     ConcurrencyLimitConfig
+	metrics *telemetry.Metrics
+
+	mu       sync.Mutex
+	limit    float64
+	inflight int
+
+	longRTT float64
+	windows int
+
+	// Current window.
+	sumRTT      time.Duration
+	samples     int
+	maxInflight int
+}
+
+func NewConcurrencyLimiter(cfg ConcurrencyLimitConfig, metrics *telemetry.Metrics) *ConcurrencyLimiter {
+	cfg.MinLimit = max(cfg.MinLimit, 1)
+	cfg.MaxLimit = max(cfg.MaxLimit, cfg.MinLimit)
+	if cfg.Tolerance <= 0 {
+		cfg.Tolerance = defaultConcurrencyTolerance
+	}
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = defaultConcurrencyWindowSize
+	}
+
+	l := &ConcurrencyLimiter{
+		cfg:     cfg,
+		metrics: metrics,
+		limit:   float64(min(max(cfg.InitialLimit, cfg.MinLimit), cfg.MaxLimit)),
+	}
+	metrics.SetConcurrencyLimit(l.limit)
+
+	return l
+}
+
+// Limit returns the current number of requests allowed in flight.
+func (l *ConcurrencyLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return int(l.limit)
+}
+
+// Acquire admits a request if fewer than Limit are in flight. Admitted
+// requests must be ended with Release.
+func (l *ConcurrencyLimiter) Acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inflight >= int(l.limit) {
+		return false
+	}
+	l.inflight++
+	l.maxInflight = max(l.maxInflight, l.inflight)
+
+	return true
+}
+
+// Release ends a request admitted by Acquire that took latency. A zero
+// latency is not sampled, for requests whose duration says nothing about
+// the server, such as streams.
+func (l *ConcurrencyLimiter) Release(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inflight--
+	if latency <= 0 {
+		return
+	}
+
+	l.sumRTT += latency
+	l.samples++
+	if l.samples >= l.cfg.WindowSize {
+		l.update(float64(l.sumRTT) / float64(l.samples))
+		l.sumRTT, l.samples, l.maxInflight = 0, 0, l.inflight
+	}
+}
+
+// update recalculates the limit from the average latency of a window.
+func (l *ConcurrencyLimiter) update(shortRTT float64) {
+	l.windows++
+	if l.windows <= concurrencyWarmupWindows {
+		l.longRTT += (shortRTT - l.longRTT) / float64(l.windows)
+	} else {
+		l.longRTT += (shortRTT - l.longRTT) * concurrencyLongRTTAlpha
+	}
+	// Let the baseline follow a lasting drop in latency quickly, or the limit
+	// would stay low long after the database has recovered.
+	if l.longRTT/shortRTT > 2 {
+		l.longRTT *= 0.95
+	}
+
+	// Far below the limit, latency says nothing about how much more the
+	// server could take.
+	if float64(l.maxInflight) < l.limit/2 {
+		return
+	}
+
+	gradient := max(0.5, min(1, l.cfg.Tolerance*l.longRTT/shortRTT))
+	next := l.limit*gradient + math.Sqrt(l.limit)
+	next = l.limit*(1-concurrencySmoothing) + next*concurrencySmoothing
+	l.limit = max(float64(l.cfg.MinLimit), min(float64(l.cfg.MaxLimit), next))
+	l.metrics.SetConcurrencyLimit(l.limit)
+}
+
+func (l *ConcurrencyLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !l.Acquire() {
+			return nil, errOverloaded
+		}
+
+		start := time.Now()
+		defer func() { l.Release(time.Since(start)) }()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor counts server streams against the limit without
+// sampling their latency. Client streams such as UploadAvatar are exempt:
+// a slow client would hold its slot for as long as it likes, so a handful
+// could shut out every other request. Server.UploadAvatar bounds them
+// itself, see UploadLimits.
+func (l *ConcurrencyLimiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if info.IsClientStream {
+			return handler(srv, ss)
+		}
+		if !l.Acquire() {
+			return errOverloaded
+		}
+		defer l.Release(0)
+
+		return handler(srv, ss)
+	}
+}
+
+var errOverloaded = status.Error(codes.Unavailable, "server is overloaded, retry later")
@@ -0,0 +1,232 @@
+package grpcapi
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+// ErrorDomain is the domain of google.rpc.ErrorInfo details attached to
+// errors of this service.
+const ErrorDomain = "account.v1"
+
+// ReasonNickNotAllowed is the google.rpc.ErrorInfo reason of nicks rejected
+// by the content filter. The "category" metadata names the wordlist that
+// matched.
+const ReasonNickNotAllowed = "NICK_NOT_ALLOWED"
+
+// ReasonPhoneTypeNotAllowed is the google.rpc.ErrorInfo reason of phone
+// numbers whose type the signup policy rejects. The "type" metadata names
+// the type, e.g. "premium_rate".
+const ReasonPhoneTypeNotAllowed = "PHONE_TYPE_NOT_ALLOWED"
+
+// ReasonRateLimited is the google.rpc.ErrorInfo reason of requests rejected
+// by a rate limit. The "scope" metadata names the limit, e.g.
+// "phone_prefix"; a google.rpc.RetryInfo detail says when to retry.
+const ReasonRateLimited = "RATE_LIMITED"
+
+// google.rpc.ErrorInfo reasons of the other domain errors. Reasons are part
+// of the API: clients switch on them, so they are never renamed.
+const (
+	ReasonInvalidNick             = "INVALID_NICK"
+	ReasonInvalidPhone            = "INVALID_PHONE"
+	ReasonNickAlreadyExists       = "NICK_ALREADY_EXISTS"
+	ReasonNickInCooldown          = "NICK_IN_COOLDOWN"
+	ReasonPhoneAlreadyExists      = "PHONE_ALREADY_EXISTS"
+	ReasonAccountNotFound         = "ACCOUNT_NOT_FOUND"
+	ReasonNickReserved            = "NICK_RESERVED"
+	ReasonInvalidNickRule         = "INVALID_NICK_RULE"
+	ReasonNickRuleExists          = "NICK_RULE_EXISTS"
+	ReasonNickRuleNotFound        = "NICK_RULE_NOT_FOUND"
+	ReasonNickRulesDisabled       = "NICK_RULES_DISABLED"
+	ReasonInvalidDisplayName      = "INVALID_DISPLAY_NAME"
+	ReasonInvalidBio              = "INVALID_BIO"
+	ReasonInvalidAvatarURL        = "INVALID_AVATAR_URL"
+	ReasonInvalidLocale           = "INVALID_LOCALE"
+	ReasonInvalidTimeZone         = "INVALID_TIME_ZONE"
+	ReasonInvalidAvatar           = "INVALID_AVATAR"
+	ReasonAvatarTooLarge          = "AVATAR_TOO_LARGE"
+	ReasonAvatarUploadDisabled    = "AVATAR_UPLOAD_DISABLED"
+	ReasonAccountNotActive        = "ACCOUNT_NOT_ACTIVE"
+	ReasonInvalidStatusTransition = "INVALID_STATUS_TRANSITION"
+	ReasonInvalidStatusReason     = "INVALID_STATUS_REASON"
+	ReasonInvalidConsistencyToken = "INVALID_CONSISTENCY_TOKEN"
+	ReasonUnauthenticated         = "UNAUTHENTICATED"
+	ReasonPermissionDenied        = "PERMISSION_DENIED"
+)
+
+// errorMapping maps a domain error to its status. Errors of a single request
+// field also get a google.rpc.BadRequest violation of field that describes
+// the rule the value broke.
+type errorMapping struct {
+	err    error
+	code   codes.Code
+	reason string
+	field  string
+	rule   string
+	// opaque errors reply with the message of err alone, hiding the cause.
+	opaque bool
+}
+
+var errorMappings = []errorMapping{
+	{err: domain.ErrInvalidNick, code: codes.InvalidArgument, reason: ReasonInvalidNick,
+		field: "nick", rule: fmt.Sprintf(`must be "@" followed by %d to %d letters, digits or underscores`, accountsvc.MinNickLength, accountsvc.MaxNickLength)},
+	{err: domain.ErrInvalidPhone, code: codes.InvalidArgument, reason: ReasonInvalidPhone,
+		field: "phone", rule: "must be a valid number in international format, or in national format of the server's default region"},
+	{err: domain.ErrPhoneTypeNotAllowed, code: codes.InvalidArgument, reason: ReasonPhoneTypeNotAllowed,
+		field: "phone", rule: "must not be of a type the signup policy rejects"},
+	{err: domain.ErrInvalidDisplayName, code: codes.InvalidArgument, reason: ReasonInvalidDisplayName,
+		field: "display_name", rule: fmt.Sprintf("must be valid UTF-8 of at most %d characters without control characters", accountsvc.MaxDisplayNameLength)},
+	{err: domain.ErrInvalidBio, code: codes.InvalidArgument, reason: ReasonInvalidBio,
+		field: "bio", rule: fmt.Sprintf("must be valid UTF-8 of at most %d characters without control characters other than newlines", accountsvc.MaxBioLength)},
+	{err: domain.ErrInvalidAvatarURL, code: codes.InvalidArgument, reason: ReasonInvalidAvatarURL,
+		field: "avatar_url", rule: fmt.Sprintf("must be an https URL with a host and without user info, of at most %d bytes", accountsvc.MaxAvatarURLLength)},
+	{err: domain.ErrInvalidLocale, code: codes.InvalidArgument, reason: ReasonInvalidLocale,
+		field: "locale", rule: `must be a BCP 47 language tag such as "en-US"`},
+	{err: domain.ErrInvalidTimeZone, code: codes.InvalidArgument, reason: ReasonInvalidTimeZone,
+		field: "time_zone", rule: `must be an IANA time zone name such as "Europe/Berlin"`},
+	{err: domain.ErrInvalidAvatar, code: codes.InvalidArgument, reason: ReasonInvalidAvatar},
+	{err: domain.ErrAvatarTooLarge, code: codes.InvalidArgument, reason: ReasonAvatarTooLarge},
+	{err: domain.ErrAvatarUploadDisabled, code: codes.Unimplemented, reason: ReasonAvatarUploadDisabled},
+	{err: domain.ErrNickReserved, code: codes.InvalidArgument, reason: ReasonNickReserved,
+		field: "nick", rule: "must not match a reserved nick rule"},
+	{err: domain.ErrInvalidNickRule, code: codes.InvalidArgument, reason: ReasonInvalidNickRule},
+	{err: domain.ErrNickNotAllowed, code: codes.InvalidArgument, reason: ReasonNickNotAllowed,
+		field: "nick", rule: "must not contain words rejected by the content filter"},
+	{err: domain.ErrNickRuleExists, code: codes.AlreadyExists, reason: ReasonNickRuleExists},
+	{err: domain.ErrNickRuleNotFound, code: codes.NotFound, reason: ReasonNickRuleNotFound},
+	{err: domain.ErrNickRulesDisabled, code: codes.Unimplemented, reason: ReasonNickRulesDisabled},
+	{err: domain.ErrNickAlreadyExists, code: codes.AlreadyExists, reason: ReasonNickAlreadyExists},
+	{err: domain.ErrNickInCooldown, code: codes.AlreadyExists, reason: ReasonNickInCooldown},
+	{err: domain.ErrPhoneAlreadyExists, code: codes.AlreadyExists, reason: ReasonPhoneAlreadyExists},
+	{err: domain.ErrAccountNotFound, code: codes.NotFound, reason: ReasonAccountNotFound},
+	{err: domain.ErrAccountNotActive, code: codes.FailedPrecondition, reason: ReasonAccountNotActive},
+	{err: domain.ErrInvalidStatusTransition, code: codes.FailedPrecondition, reason: ReasonInvalidStatusTransition},
+	{err: domain.ErrInvalidStatusReason, code: codes.InvalidArgument, reason: ReasonInvalidStatusReason,
+		field: "reason", rule: "must be a status reason other than UNSPECIFIED"},
+	{err: domain.ErrRateLimited, code: codes.ResourceExhausted, reason: ReasonRateLimited},
+	{err: domain.ErrInvalidConsistencyToken, code: codes.InvalidArgument, reason: ReasonInvalidConsistencyToken,
+		field: "consistency_token", rule: "must be a token returned by this service", opaque: true},
+	{err: domain.ErrUnauthenticated, code: codes.Unauthenticated, reason: ReasonUnauthenticated},
+	{err: domain.ErrPermissionDenied, code: codes.PermissionDenied, reason: ReasonPermissionDenied},
+}
+
+var errorMappingsByReason = func() map[string]errorMapping {
+	byReason := make(map[string]errorMapping, len(errorMappings))
+	for _, m := range errorMappings {
+		byReason[m.reason] = m
+	}
+
+	return byReason
+}()
+
+func mapDomainError(err error) error {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			return m.status(err)
+		}
+	}
+
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func (m errorMapping) status(err error) error {
+	msg := err.Error()
+	if m.opaque {
+		msg = m.err.Error()
+	}
+
+	info := &errdetails.ErrorInfo{Reason: m.reason, Domain: ErrorDomain}
+	details := []protoadapt.MessageV1{info}
+	rule := m.rule
+
+	var nickNotAllowed *domain.NickNotAllowedError
+	var phoneTypeNotAllowed *domain.PhoneTypeNotAllowedError
+	var limited *domain.RateLimitedError
+	switch {
+	case errors.As(err, &nickNotAllowed):
+		info.Metadata = map[string]string{"category": nickNotAllowed.Category}
+		rule = fmt.Sprintf("must not contain words of the %s wordlist", nickNotAllowed.Category)
+	case errors.As(err, &phoneTypeNotAllowed):
+		info.Metadata = map[string]string{"type": string(phoneTypeNotAllowed.Type)}
+		rule = fmt.Sprintf("must not be a %s number", phoneTypeNotAllowed.Type)
+	case errors.As(err, &limited):
+		info.Metadata = map[string]string{"scope": limited.Scope}
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(limited.RetryAfter)})
+	}
+
+	if m.field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: m.field, Description: rule, Reason: m.reason},
+			},
+		})
+	}
+
+	st, detailErr := status.New(m.code, msg).WithDetails(details...)
+	if detailErr != nil {
+		return status.Error(m.code, msg)
+	}
+
+	return st.Err()
+}
+
+// StatusError returns the gRPC error the server replies with for err, the
+// inverse of DomainError. Status errors are returned as they are.
+func StatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return mapDomainError(err)
+}
+
+// DomainError converts an error returned by an account service client back
+// into the domain error it was mapped from, by the reason of its
+// google.rpc.ErrorInfo detail, so that callers can match it with errors.Is.
+// NickNotAllowedError, PhoneTypeNotAllowedError and RateLimitedError are
+// rebuilt from the metadata and RetryInfo. Other errors are returned as they
+// are.
+func DomainError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+
+	var info *errdetails.ErrorInfo
+	var retry *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.RetryInfo:
+			retry = d
+		}
+	}
+	if info == nil || info.GetDomain() != ErrorDomain {
+		return err
+	}
+
+	switch info.GetReason() {
+	case ReasonNickNotAllowed:
+		return &domain.NickNotAllowedError{Category: info.GetMetadata()["category"]}
+	case ReasonPhoneTypeNotAllowed:
+		return &domain.PhoneTypeNotAllowedError{Type: domain.PhoneType(info.GetMetadata()["type"])}
+	case ReasonRateLimited:
+		return &domain.RateLimitedError{Scope: info.GetMetadata()["scope"], RetryAfter: retry.GetRetryDelay().AsDuration()}
+	}
+
+	if m, ok := errorMappingsByReason[info.GetReason()]; ok {
+		return m.err
+	}
+
+	return err
+}
@@ -42,3 +42,34 @@ func UnaryMetricsInterceptor(metrics *telemetry.Metrics, logger *slog.Logger) gr
 		return resp, err
 	}
 }
+
+func StreamMetricsInterceptor(metrics *telemetry.Metrics, logger *slog.Logger) grpc.StreamServerInterceptor {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		method := path.Base(info.FullMethod)
+		start := time.Now()
+
+		metrics.IncRPCInFlight()
+		defer metrics.DecRPCInFlight()
+
+		err := handler(srv, ss)
+		code := status.Code(err).String()
+		metrics.ObserveRPC(method, code, time.Since(start))
+
+		logger.Info("grpc request",
+			"method", method,
+			"code", code,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+
+		return err
+	}
+}
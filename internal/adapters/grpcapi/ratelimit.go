@@ -0,0 +1,232 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/netip"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+// Scopes of server rate limits, used in the "scope" metadata of
+// ReasonRateLimited errors and to label metrics.
+const (
+	RateLimitScopeMethod = "method"
+	RateLimitScopeClient = "client"
+)
+
+// DefaultRateLimitMethod keys the quota of methods without their own in
+// RateLimitConfig.
+const DefaultRateLimitMethod = "*"
+
+// Rate is a token bucket quota: PerSecond requests on average with bursts of
+// up to Burst. The zero Rate is unlimited.
+type Rate struct {
+	PerSecond float64
+	Burst     int
+}
+
+func (r Rate) unlimited() bool {
+	return r.PerSecond <= 0 || r.Burst <= 0
+}
+
+// RateLimitConfig holds token bucket quotas keyed by method name, e.g.
+// "CreateAccount", or DefaultRateLimitMethod.
+type RateLimitConfig struct {
+	// Methods limits each method for all clients together.
+	Methods map[string]Rate
+	// Clients limits each method for every client on its own. A client is
+	// identified by its TLS certificate, or CallerIDHeader passed on by a
+	// trusted proxy, or else by its IPv4 address or IPv6 /64. A
+	// CallerIDHeader sent directly is ignored: clients could pick a new one
+	// per request.
+	Clients map[string]Rate
+	// TrustedProxies are passed to client identification as with
+	// WithTrustedProxies.
+	TrustedProxies []netip.Prefix
+}
+
+// ParseRates parses "<method>=<per second>:<burst>" entries such as
+// "CreateAccount=5:20" or "*=100:200".
+func ParseRates(entries []string) (map[string]Rate, error) {
+	rates := make(map[string]Rate, len(entries))
+	for _, entry := range entries {
+		method, quota, ok := strings.Cut(entry, "=")
+		perSecond, burst, ok2 := strings.Cut(quota, ":")
+		if !ok || !ok2 || method == "" {
+			return nil, fmt.Errorf("invalid rate %q, expected method=per_second:burst", entry)
+		}
+
+		var r Rate
+		var err error
+		if r.PerSecond, err = strconv.ParseFloat(perSecond, 64); err != nil || r.PerSecond < 0 {
+			return nil, fmt.Errorf("invalid rate %q: bad requests per second", entry)
+		}
+		if r.Burst, err = strconv.Atoi(burst); err != nil || r.Burst < 0 {
+			return nil, fmt.Errorf("invalid rate %q: bad burst", entry)
+		}
+		rates[method] = r
+	}
+
+	return rates, nil
+}
+
+// RateLimiter rejects requests over their quota with ResourceExhausted and
+// a RetryInfo detail, before they reach the service.
+type RateLimiter struct {
+	cfg     RateLimitConfig
+	metrics *telemetry.Metrics
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewRateLimiter(cfg RateLimitConfig, metrics *telemetry.Metrics) *RateLimiter {
+	return &RateLimiter{cfg: cfg, metrics: metrics, buckets: make(map[string]*tokenBucket)}
+}
+
+func (l *RateLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.allow(ctx, path.Base(info.FullMethod)); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor counts each stream as one request when it starts.
+func (l *RateLimiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.allow(ss.Context(), path.Base(info.FullMethod)); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func (l *RateLimiter) allow(ctx context.Context, method string) error {
+	methodRate := rateFor(l.cfg.Methods, method)
+	clientRate := rateFor(l.cfg.Clients, method)
+	if methodRate.unlimited() && clientRate.unlimited() {
+		return nil
+	}
+
+	var client string
+	if !clientRate.unlimited() {
+		caller := callerFromContext(ctx, l.cfg.TrustedProxies)
+		switch {
+		case caller.ID != "" && caller.Authenticated:
+			client = "id:" + caller.ID
+		case caller.IP != "":
+			client = "ip:" + caller.IPBlock()
+		}
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	var clientBucket, methodBucket *tokenBucket
+	if client != "" {
+		clientBucket = l.bucket(method+"\x00"+client, clientRate, now)
+	}
+	if !methodRate.unlimited() {
+		methodBucket = l.bucket(method, methodRate, now)
+	}
+
+	// Check both buckets before taking from either, so that a request
+	// rejected by one does not use up the quota of the other.
+	if wait := clientBucket.wait(now); wait > 0 {
+		return l.reject(method, RateLimitScopeClient, wait)
+	}
+	if wait := methodBucket.wait(now); wait > 0 {
+		return l.reject(method, RateLimitScopeMethod, wait)
+	}
+	clientBucket.take()
+	methodBucket.take()
+
+	return nil
+}
+
+func (l *RateLimiter) reject(method, scope string, wait time.Duration) error {
+	l.metrics.IncRateLimited(method, scope)
+	return mapDomainError(&domain.RateLimitedError{Scope: scope, RetryAfter: wait})
+}
+
+func (l *RateLimiter) bucket(key string, rate Rate, now time.Time) *tokenBucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{rate: rate, tokens: float64(rate.Burst), updated: now}
+		l.buckets[key] = b
+	}
+
+	return b
+}
+
+// sweep drops buckets that have refilled, which behave like new ones, at
+// most once a minute.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if b.refill(now) >= float64(b.rate.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func rateFor(rates map[string]Rate, method string) Rate {
+	if r, ok := rates[method]; ok {
+		return r
+	}
+
+	return rates[DefaultRateLimitMethod]
+}
+
+type tokenBucket struct {
+	rate    Rate
+	tokens  float64
+	updated time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) float64 {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(float64(b.rate.Burst), b.tokens+elapsed.Seconds()*b.rate.PerSecond)
+		b.updated = now
+	}
+
+	return b.tokens
+}
+
+// wait returns how long until a token is available, zero if one is. A nil
+// bucket is unlimited.
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	if b == nil || b.refill(now) >= 1 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil((1 - b.tokens) / b.rate.PerSecond * float64(time.Second)))
+}
+
+// take removes a token after wait returned zero.
+func (b *tokenBucket) take() {
+	if b != nil {
+		b.tokens--
+	}
+}
@@ -3,41 +3,97 @@ package grpcapi
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
+	"net/netip"
+	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
 	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/phone"
 	accountsvc "github.com/kvetinski/account/internal/service/account"
 )
 
+// ConsistencyTokenHeader carries the read-your-writes token in response
+// headers of mutating RPCs. GetAccount also accepts it as request metadata
+// when the request field is empty.
+const ConsistencyTokenHeader = "x-consistency-token"
+
 type Server struct {
 	accountv1.UnimplementedAccountServiceServer
 
 	svc    *accountsvc.Service
 	logger *slog.Logger
+
+	trustedProxies []netip.Prefix
+
+	uploadLimits UploadLimits
+	uploads      chan struct{}
 }
 
-func NewServer(svc *accountsvc.Service, logger *slog.Logger) *Server {
+const (
+	defaultMaxConcurrentUploads = 10
+	defaultUploadTimeout        = 30 * time.Second
+)
+
+// UploadLimits bound avatar uploads, which the concurrency limiter does not
+// count because a slow client would hold its slot for as long as it likes.
+type UploadLimits struct {
+	// MaxConcurrent is the number of uploads in flight, 10 when zero.
+	// Uploads over it fail with Unavailable.
+	MaxConcurrent int
+	// Timeout is how long a client has to send an upload, 30s when zero.
+	// Slower uploads fail with DeadlineExceeded.
+	Timeout time.Duration
+}
+
+// WithUploadLimits bounds avatar uploads.
+func WithUploadLimits(limits UploadLimits) ServerOption {
+	return func(s *Server) {
+		s.uploadLimits = limits
+	}
+}
+
+func NewServer(svc *accountsvc.Service, logger *slog.Logger, opts ...ServerOption) *Server {
 	if logger == nil {
 		logger = slog.Default()
 	}
 
-	return &Server{svc: svc, logger: logger}
+	s := &Server{svc: svc, logger: logger}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.uploadLimits.MaxConcurrent <= 0 {
+		s.uploadLimits.MaxConcurrent = defaultMaxConcurrentUploads
+	}
+	if s.uploadLimits.Timeout <= 0 {
+		s.uploadLimits.Timeout = defaultUploadTimeout
+	}
+	s.uploads = make(chan struct{}, s.uploadLimits.MaxConcurrent)
+
+	return s
 }
 
 func (s *Server) CreateAccount(ctx context.Context, req *accountv1.CreateAccountRequest) (*accountv1.AccountResponse, error) {
-	acc, err := s.svc.Create(ctx, req.GetPhone())
+	acc, err := s.svc.Create(domain.WithCaller(ctx, s.caller(ctx)), domain.NewAccount{
+		Phone:                     req.GetPhone(),
+		Locale:                    req.GetLocale(),
+		Nick:                      req.GetNick(),
+		GenerateNickIfUnavailable: req.GetGenerateNickIfUnavailable(),
+	})
 	if err != nil {
 		return nil, mapDomainError(err)
 	}
 
-	return &accountv1.AccountResponse{Account: toProtoAccount(acc)}, nil
+	return &accountv1.AccountResponse{Account: toProtoAccount(acc), ConsistencyToken: s.issueConsistencyToken(ctx)}, nil
 }
 
 func (s *Server) GetAccount(ctx context.Context, req *accountv1.GetAccountRequest) (*accountv1.AccountResponse, error) {
@@ -46,6 +102,7 @@ func (s *Server) GetAccount(ctx context.Context, req *accountv1.GetAccountReques
 		return nil, err
 	}
 
+	ctx = domain.WithConsistencyToken(ctx, requestConsistencyToken(ctx, req.GetConsistencyToken()))
 	acc, err := s.svc.GetByID(ctx, id)
 	if err != nil {
 		return nil, mapDomainError(err)
@@ -54,6 +111,62 @@ func (s *Server) GetAccount(ctx context.Context, req *accountv1.GetAccountReques
 	return &accountv1.AccountResponse{Account: toProtoAccount(acc)}, nil
 }
 
+func (s *Server) GetAccountByNick(ctx context.Context, req *accountv1.GetAccountByNickRequest) (*accountv1.AccountResponse, error) {
+	acc, err := s.svc.GetByNick(ctx, req.GetNick())
+	if err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	return &accountv1.AccountResponse{Account: toProtoAccount(acc)}, nil
+}
+
+func (s *Server) ListNickHistory(ctx context.Context, req *accountv1.ListNickHistoryRequest) (*accountv1.ListNickHistoryResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.svc.ListNickHistory(ctx, id)
+	if err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	resp := &accountv1.ListNickHistoryResponse{Changes: make([]*accountv1.NickChange, 0, len(history))}
+	for _, c := range history {
+		resp.Changes = append(resp.Changes, &accountv1.NickChange{Nick: c.Nick, ReleasedAt: timestamppb.New(c.ReleasedAt)})
+	}
+
+	return resp, nil
+}
+
+func (s *Server) CheckNick(ctx context.Context, req *accountv1.CheckNickRequest) (*accountv1.CheckNickResponse, error) {
+	id := uuid.Nil
+	if req.GetAccountId() != "" {
+		var err error
+		if id, err = parseID(req.GetAccountId()); err != nil {
+			return nil, err
+		}
+	}
+
+	check, err := s.svc.CheckNick(ctx, id, req.GetNick(), int(req.GetMaxSuggestions()))
+	if err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	resp := &accountv1.CheckNickResponse{
+		Nick:        check.Nick,
+		Valid:       check.Valid,
+		Available:   check.Available,
+		Reasons:     make([]accountv1.NickCheckReason, 0, len(check.Reasons)),
+		Suggestions: check.Suggestions,
+	}
+	for _, reason := range check.Reasons {
+		resp.Reasons = append(resp.Reasons, protoNickCheckReasons[reason])
+	}
+
+	return resp, nil
+}
+
 func (s *Server) UpdateNick(ctx context.Context, req *accountv1.UpdateNickRequest) (*accountv1.AccountResponse, error) {
 	id, err := parseID(req.GetId())
 	if err != nil {
@@ -65,7 +178,120 @@ func (s *Server) UpdateNick(ctx context.Context, req *accountv1.UpdateNickReques
 		return nil, mapDomainError(err)
 	}
 
-	return &accountv1.AccountResponse{Account: toProtoAccount(acc)}, nil
+	return &accountv1.AccountResponse{Account: toProtoAccount(acc), ConsistencyToken: s.issueConsistencyToken(ctx)}, nil
+}
+
+func (s *Server) UpdateProfile(ctx context.Context, req *accountv1.UpdateProfileRequest) (*accountv1.AccountResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := s.svc.UpdateProfile(ctx, id, domain.ProfileUpdate{
+		DisplayName: req.DisplayName,
+		Bio:         req.Bio,
+		AvatarURL:   req.AvatarUrl,
+		Locale:      req.Locale,
+		TimeZone:    req.TimeZone,
+	})
+	if err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	return &accountv1.AccountResponse{Account: toProtoAccount(acc), ConsistencyToken: s.issueConsistencyToken(ctx)}, nil
+}
+
+func (s *Server) UploadAvatar(stream accountv1.AccountService_UploadAvatarServer) error {
+	select {
+	case s.uploads <- struct{}{}:
+		defer func() { <-s.uploads }()
+	default:
+		return errTooManyUploads
+	}
+
+	recvCtx, cancel := context.WithTimeout(stream.Context(), s.uploadLimits.Timeout)
+	defer cancel()
+	chunks := &avatarChunkReader{stream: stream, ctx: recvCtx}
+
+	first, err := chunks.recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "missing account id")
+		}
+		return err
+	}
+
+	id, err := parseID(first.GetId())
+	if err != nil {
+		return err
+	}
+
+	acc, err := s.svc.UploadAvatar(stream.Context(), id, chunks)
+	if err != nil {
+		if chunks.err != nil {
+			return chunks.err
+		}
+		return mapDomainError(err)
+	}
+
+	return stream.SendAndClose(&accountv1.AccountResponse{Account: toProtoAccount(acc), ConsistencyToken: s.issueConsistencyToken(stream.Context())})
+}
+
+var errTooManyUploads = status.Error(codes.Unavailable, "too many avatar uploads in flight, retry later")
+
+// avatarChunkReader reads the chunks of an UploadAvatar stream as one byte
+// stream, until ctx is done. A failure to receive is kept in err so it
+// reaches the client as is.
+type avatarChunkReader struct {
+	stream accountv1.AccountService_UploadAvatarServer
+	ctx    context.Context
+	buf    []byte
+	err    error
+}
+
+type avatarRecvResult struct {
+	req *accountv1.UploadAvatarRequest
+	err error
+}
+
+// recv receives the next message, or fails once ctx is done. Recv itself
+// cannot be interrupted; a pending one returns when the handler does.
+func (r *avatarChunkReader) recv() (*accountv1.UploadAvatarRequest, error) {
+	done := make(chan avatarRecvResult, 1)
+	go func() {
+		req, err := r.stream.Recv()
+		done <- avatarRecvResult{req: req, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.req, res.err
+	case <-r.ctx.Done():
+		return nil, status.FromContextError(r.ctx.Err()).Err()
+	}
+}
+
+func (r *avatarChunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.recv()
+		if errors.Is(err, io.EOF) {
+			return 0, io.EOF
+		}
+		if err != nil {
+			r.err = err
+			return 0, err
+		}
+		if _, ok := req.GetPayload().(*accountv1.UploadAvatarRequest_Chunk); !ok {
+			r.err = status.Error(codes.InvalidArgument, "expected avatar chunk")
+			return 0, r.err
+		}
+		r.buf = req.GetChunk()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
 }
 
 func (s *Server) DeleteAccount(ctx context.Context, req *accountv1.DeleteAccountRequest) (*emptypb.Empty, error) {
@@ -77,10 +303,111 @@ func (s *Server) DeleteAccount(ctx context.Context, req *accountv1.DeleteAccount
 	if err = s.svc.Delete(ctx, id); err != nil {
 		return nil, mapDomainError(err)
 	}
+	s.issueConsistencyToken(ctx)
 
 	return &emptypb.Empty{}, nil
 }
 
+func (s *Server) SuspendAccount(ctx context.Context, req *accountv1.UpdateStatusRequest) (*accountv1.AccountResponse, error) {
+	return s.updateStatus(ctx, req, s.svc.Suspend)
+}
+
+func (s *Server) ReinstateAccount(ctx context.Context, req *accountv1.UpdateStatusRequest) (*accountv1.AccountResponse, error) {
+	return s.updateStatus(ctx, req, s.svc.Reinstate)
+}
+
+func (s *Server) BanAccount(ctx context.Context, req *accountv1.UpdateStatusRequest) (*accountv1.AccountResponse, error) {
+	return s.updateStatus(ctx, req, s.svc.Ban)
+}
+
+func (s *Server) updateStatus(
+	ctx context.Context,
+	req *accountv1.UpdateStatusRequest,
+	transition func(context.Context, uuid.UUID, domain.StatusReason) (domain.Account, error),
+) (*accountv1.AccountResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := transition(ctx, id, fromProtoStatusReason(req.GetReason()))
+	if err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	return &accountv1.AccountResponse{Account: toProtoAccount(acc), ConsistencyToken: s.issueConsistencyToken(ctx)}, nil
+}
+
+func (s *Server) ListNickRules(ctx context.Context, _ *accountv1.ListNickRulesRequest) (*accountv1.ListNickRulesResponse, error) {
+	rules, err := s.svc.ListNickRules(ctx)
+	if err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	resp := &accountv1.ListNickRulesResponse{Rules: make([]*accountv1.NickRule, 0, len(rules))}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, toProtoNickRule(rule))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) AddNickRule(ctx context.Context, req *accountv1.AddNickRuleRequest) (*accountv1.NickRule, error) {
+	rule, err := s.svc.AddNickRule(ctx, domain.NickRule{
+		Kind:   fromProtoNickRuleKind(req.GetKind()),
+		Value:  req.GetValue(),
+		Reason: req.GetReason(),
+	})
+	if err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	return toProtoNickRule(rule), nil
+}
+
+func (s *Server) RemoveNickRule(ctx context.Context, req *accountv1.RemoveNickRuleRequest) (*emptypb.Empty, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid nick rule id")
+	}
+
+	if err = s.svc.RemoveNickRule(ctx, id); err != nil {
+		return nil, mapDomainError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// issueConsistencyToken returns a token for the mutation just completed and
+// also sends it as a response header. The mutation has already succeeded, so
+// a failure only costs the caller the read-your-writes guarantee.
+func (s *Server) issueConsistencyToken(ctx context.Context) string {
+	token, err := s.svc.ConsistencyToken(ctx)
+	if err != nil {
+		s.logger.Warn("issue consistency token failed", "error", err)
+		return ""
+	}
+
+	if token != "" {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(ConsistencyTokenHeader, token))
+	}
+
+	return token
+}
+
+func requestConsistencyToken(ctx context.Context, token string) string {
+	if token != "" {
+		return token
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(ConsistencyTokenHeader); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
 func parseID(raw string) (uuid.UUID, error) {
 	id, err := uuid.Parse(raw)
 	if err != nil {
@@ -90,30 +417,22 @@ func parseID(raw string) (uuid.UUID, error) {
 	return id, nil
 }
 
-func mapDomainError(err error) error {
-	switch {
-	case errors.Is(err, domain.ErrInvalidNick):
-		return status.Error(codes.InvalidArgument, err.Error())
-	case errors.Is(err, domain.ErrInvalidPhone):
-		return status.Error(codes.InvalidArgument, err.Error())
-	case errors.Is(err, domain.ErrNickAlreadyExists):
-		return status.Error(codes.AlreadyExists, err.Error())
-	case errors.Is(err, domain.ErrPhoneAlreadyExists):
-		return status.Error(codes.AlreadyExists, err.Error())
-	case errors.Is(err, domain.ErrAccountNotFound):
-		return status.Error(codes.NotFound, err.Error())
-	default:
-		return status.Error(codes.Internal, "internal server error")
-	}
-}
-
 func toProtoAccount(acc domain.Account) *accountv1.Account {
 	out := &accountv1.Account{
-		Id:        acc.ID.String(),
-		Nick:      acc.Nick,
-		Phone:     acc.Phone,
-		CreatedAt: timestamppb.New(acc.CreatedAt),
-		UpdatedAt: timestamppb.New(acc.UpdatedAt),
+		Id:           acc.ID.String(),
+		Nick:         acc.Nick,
+		Phone:        acc.Phone,
+		Status:       toProtoStatus(acc.Status),
+		StatusReason: toProtoStatusReason(acc.StatusReason),
+		Profile:      toProtoProfile(acc.Profile),
+		PhoneType:    protoPhoneTypes[acc.PhoneType],
+		PhoneFlagged: acc.PhoneFlagged,
+		CreatedAt:    timestamppb.New(acc.CreatedAt),
+		UpdatedAt:    timestamppb.New(acc.UpdatedAt),
+	}
+	// Stored phones are E.164, so no default region is needed.
+	if n, err := phone.Parse(acc.Phone, ""); err == nil {
+		out.PhoneCountry = n.Region
 	}
 
 	if acc.DeletedAt != nil {
@@ -122,3 +441,96 @@ func toProtoAccount(acc domain.Account) *accountv1.Account {
 
 	return out
 }
+
+func toProtoProfile(p domain.Profile) *accountv1.Profile {
+	return &accountv1.Profile{
+		DisplayName:        p.DisplayName,
+		Bio:                p.Bio,
+		AvatarUrl:          p.AvatarURL,
+		AvatarThumbnailUrl: p.AvatarThumbnailURL,
+		Locale:             p.Locale,
+		TimeZone:           p.TimeZone,
+	}
+}
+
+var protoStatuses = map[domain.Status]accountv1.AccountStatus{
+	domain.StatusActive:    accountv1.AccountStatus_ACCOUNT_STATUS_ACTIVE,
+	domain.StatusSuspended: accountv1.AccountStatus_ACCOUNT_STATUS_SUSPENDED,
+	domain.StatusBanned:    accountv1.AccountStatus_ACCOUNT_STATUS_BANNED,
+	domain.StatusDeleted:   accountv1.AccountStatus_ACCOUNT_STATUS_DELETED,
+}
+
+var protoPhoneTypes = map[domain.PhoneType]accountv1.PhoneType{
+	domain.PhoneTypeUnknown:     accountv1.PhoneType_PHONE_TYPE_UNKNOWN,
+	domain.PhoneTypeMobile:      accountv1.PhoneType_PHONE_TYPE_MOBILE,
+	domain.PhoneTypeFixedLine:   accountv1.PhoneType_PHONE_TYPE_FIXED_LINE,
+	domain.PhoneTypeVoIP:        accountv1.PhoneType_PHONE_TYPE_VOIP,
+	domain.PhoneTypePremiumRate: accountv1.PhoneType_PHONE_TYPE_PREMIUM_RATE,
+	domain.PhoneTypeTollFree:    accountv1.PhoneType_PHONE_TYPE_TOLL_FREE,
+}
+
+var protoStatusReasons = map[domain.StatusReason]accountv1.StatusReason{
+	domain.StatusReasonSpam:           accountv1.StatusReason_STATUS_REASON_SPAM,
+	domain.StatusReasonAbuse:          accountv1.StatusReason_STATUS_REASON_ABUSE,
+	domain.StatusReasonFraud:          accountv1.StatusReason_STATUS_REASON_FRAUD,
+	domain.StatusReasonImpersonation:  accountv1.StatusReason_STATUS_REASON_IMPERSONATION,
+	domain.StatusReasonTermsViolation: accountv1.StatusReason_STATUS_REASON_TERMS_VIOLATION,
+	domain.StatusReasonAppealGranted:  accountv1.StatusReason_STATUS_REASON_APPEAL_GRANTED,
+	domain.StatusReasonOther:          accountv1.StatusReason_STATUS_REASON_OTHER,
+}
+
+func toProtoStatus(st domain.Status) accountv1.AccountStatus {
+	return protoStatuses[st]
+}
+
+func toProtoStatusReason(reason domain.StatusReason) accountv1.StatusReason {
+	return protoStatusReasons[reason]
+}
+
+// fromProtoStatusReason returns an invalid reason for UNSPECIFIED and unknown
+// values so the service rejects them.
+func fromProtoStatusReason(reason accountv1.StatusReason) domain.StatusReason {
+	for r, p := range protoStatusReasons {
+		if p == reason {
+			return r
+		}
+	}
+
+	return ""
+}
+
+var protoNickRuleKinds = map[domain.NickRuleKind]accountv1.NickRuleKind{
+	domain.NickRuleExact:   accountv1.NickRuleKind_NICK_RULE_KIND_EXACT,
+	domain.NickRulePrefix:  accountv1.NickRuleKind_NICK_RULE_KIND_PREFIX,
+	domain.NickRulePattern: accountv1.NickRuleKind_NICK_RULE_KIND_PATTERN,
+}
+
+var protoNickCheckReasons = map[domain.NickCheckReason]accountv1.NickCheckReason{
+	domain.NickCheckInvalidFormat: accountv1.NickCheckReason_NICK_CHECK_REASON_INVALID_FORMAT,
+	domain.NickCheckReserved:      accountv1.NickCheckReason_NICK_CHECK_REASON_RESERVED,
+	domain.NickCheckNotAllowed:    accountv1.NickCheckReason_NICK_CHECK_REASON_NOT_ALLOWED,
+	domain.NickCheckTaken:         accountv1.NickCheckReason_NICK_CHECK_REASON_TAKEN,
+	domain.NickCheckCooldown:      accountv1.NickCheckReason_NICK_CHECK_REASON_COOLDOWN,
+}
+
+func toProtoNickRule(rule domain.NickRule) *accountv1.NickRule {
+	return &accountv1.NickRule{
+		Id:        rule.ID.String(),
+		Kind:      protoNickRuleKinds[rule.Kind],
+		Value:     rule.Value,
+		Reason:    rule.Reason,
+		CreatedAt: timestamppb.New(rule.CreatedAt),
+	}
+}
+
+// fromProtoNickRuleKind returns an invalid kind for UNSPECIFIED and unknown
+// values so the service rejects them.
+func fromProtoNickRuleKind(kind accountv1.NickRuleKind) domain.NickRuleKind {
+	for k, p := range protoNickRuleKinds {
+		if p == kind {
+			return k
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+const replicaLSNPollInterval = 5 * time.Millisecond
+
+var lsnPattern = regexp.MustCompile(`^[0-9A-F]{1,8}/[0-9A-F]{1,8}$`)
+
+// ConsistencyToken returns an opaque token for the primary's current WAL
+// position. Reads carrying it never observe a replica that has not replayed
+// up to that position. Without replicas every read hits the primary and the
+// token is empty.
+func (r *Repository) ConsistencyToken(ctx context.Context) (string, error) {
+	if len(r.replicas) == 0 {
+		return "", nil
+	}
+
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("current_wal_lsn", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	var lsn string
+	if err := r.pool.QueryRow(ctx, `SELECT pg_current_wal_lsn()::text`).Scan(&lsn); err != nil {
+		status = "error"
+		return "", fmt.Errorf("current wal lsn: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(lsn)), nil
+}
+
+func decodeConsistencyToken(token string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !lsnPattern.Match(raw) {
+		return "", domain.ErrInvalidConsistencyToken
+	}
+
+	return string(raw), nil
+}
+
+// waitForLSN reports whether rep has replayed WAL up to lsn, polling for at
+// most r.replicaMaxWait before giving up so the caller can use the primary.
+func (r *Repository) waitForLSN(ctx context.Context, rep *replica, lsn string) (bool, error) {
+	// pg_last_wal_replay_lsn is NULL on a server that is not in recovery,
+	// which is then as current as the primary.
+	const q = `SELECT COALESCE(pg_last_wal_replay_lsn() >= $1::pg_lsn, true)`
+
+	deadline := time.Now().Add(r.replicaMaxWait)
+	for {
+		var caughtUp bool
+		err := r.observeRead("wait_replica_lsn", telemetry.DBRoleReplica, func() error {
+			return rep.pool.QueryRow(ctx, q, lsn).Scan(&caughtUp)
+		})
+		if err != nil || caughtUp {
+			return caughtUp, err
+		}
+
+		if time.Now().Add(replicaLSNPollInterval).After(deadline) {
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(replicaLSNPollInterval):
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+const invalidationChannel = "account_cache_invalidation"
+
+type InvalidationHandler interface {
+	Invalidate(id uuid.UUID)
+	Purge()
+}
+
+// Invalidations broadcasts account cache invalidations between service
+// replicas over Postgres LISTEN/NOTIFY on the primary.
+type Invalidations struct {
+	pool    *pgxpool.Pool
+	metrics *telemetry.Metrics
+}
+
+func NewInvalidations(pool *pgxpool.Pool, metrics *telemetry.Metrics) *Invalidations {
+	return &Invalidations{pool: pool, metrics: metrics}
+}
+
+func (n *Invalidations) Publish(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		n.metrics.ObserveDB("notify_invalidation", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	if _, err := n.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, invalidationChannel, id.String()); err != nil {
+		status = "error"
+		return fmt.Errorf("notify invalidation: %w", err)
+	}
+
+	return nil
+}
+
+// Listen subscribes on a dedicated connection and forwards invalidations to
+// handler until ctx is done or the connection fails. Notifications sent while
+// not subscribed are lost, so handler is purged once the subscription is
+// established; callers reconnect by calling Listen again.
+func (n *Invalidations) Listen(ctx context.Context, handler InvalidationHandler) error {
+	pooled, err := n.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire listener conn: %w", err)
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err = conn.Exec(ctx, "LISTEN "+invalidationChannel); err != nil {
+		return fmt.Errorf("listen %s: %w", invalidationChannel, err)
+	}
+	handler.Purge()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for invalidation: %w", err)
+		}
+
+		id, err := uuid.Parse(notification.Payload)
+		if err != nil {
+			continue
+		}
+		handler.Invalidate(id)
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+// ListNickRules reads from the primary so that a rule is enforced as soon as
+// AddNickRule returns, without waiting for replicas to catch up.
+func (r *Repository) ListNickRules(ctx context.Context) ([]domain.NickRule, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("list_nick_rules", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		SELECT id, kind, value, reason, created_at
+		FROM nick_rules
+		ORDER BY kind, value
+	`
+
+	rows, err := r.pool.Query(ctx, q)
+	if err != nil {
+		status = "error"
+		return nil, fmt.Errorf("list nick rules: %w", err)
+	}
+
+	rules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.NickRule, error) {
+		var rule domain.NickRule
+		err := row.Scan(&rule.ID, &rule.Kind, &rule.Value, &rule.Reason, &rule.CreatedAt)
+		return rule, err
+	})
+	if err != nil {
+		status = "error"
+		return nil, fmt.Errorf("list nick rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// AddNickRule returns domain.ErrNickRuleExists if a rule of the same kind and
+// value exists.
+func (r *Repository) AddNickRule(ctx context.Context, rule domain.NickRule) (domain.NickRule, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("add_nick_rule", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		INSERT INTO nick_rules (id, kind, value, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	if err := r.pool.QueryRow(ctx, q, rule.ID, string(rule.Kind), rule.Value, rule.Reason).Scan(&rule.CreatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			status = "conflict"
+			return domain.NickRule{}, domain.ErrNickRuleExists
+		}
+
+		status = "error"
+		return domain.NickRule{}, fmt.Errorf("add nick rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *Repository) DeleteNickRule(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("delete_nick_rule", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM nick_rules WHERE id = $1`, id)
+	if err != nil {
+		status = "error"
+		return fmt.Errorf("delete nick rule: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		status = "not_found"
+		return domain.ErrNickRuleNotFound
+	}
+
+	return nil
+}
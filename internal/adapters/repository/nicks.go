@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+// GetByNick returns the account currently holding nick or, failing that, the
+// live account that released it most recently within redirectPeriod. Nicks
+// are compared ignoring case.
+func (r *Repository) GetByNick(ctx context.Context, nick string, redirectPeriod time.Duration) (domain.Account, error) {
+	const (
+		current = `
+			SELECT ` + accountColumns + `
+			FROM accounts
+			WHERE nick_key = lower($1) AND deleted_at IS NULL
+		`
+		redirect = `
+			SELECT ` + prefixedAccountColumns + `
+			FROM nick_history h
+			JOIN accounts a ON a.id = h.account_id
+			WHERE lower(h.nick) = lower($1)
+			  AND h.released_at > NOW() - make_interval(secs => $2)
+			  AND a.deleted_at IS NULL
+			ORDER BY h.released_at DESC, h.id DESC
+			LIMIT 1
+		`
+	)
+
+	var a domain.Account
+	err := r.read(ctx, "get_by_nick", func(db *pgxpool.Pool) error {
+		var err error
+		a, err = scanAccount(db.QueryRow(ctx, current, nick))
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		a, err = scanAccount(db.QueryRow(ctx, redirect, nick, redirectPeriod.Seconds()))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Account{}, domain.ErrAccountNotFound
+		}
+
+		return domain.Account{}, fmt.Errorf("get account by nick: %w", err)
+	}
+
+	return a, nil
+}
+
+// ListNickHistory returns the nicks the account has released, newest first.
+func (r *Repository) ListNickHistory(ctx context.Context, id uuid.UUID) ([]domain.NickChange, error) {
+	const q = `
+		SELECT nick, released_at
+		FROM nick_history
+		WHERE account_id = $1
+		ORDER BY released_at DESC, id DESC
+	`
+
+	var history []domain.NickChange
+	err := r.read(ctx, "list_nick_history", func(db *pgxpool.Pool) error {
+		rows, err := db.Query(ctx, q, id)
+		if err != nil {
+			return err
+		}
+
+		history, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.NickChange, error) {
+			var c domain.NickChange
+			err := row.Scan(&c.Nick, &c.ReleasedAt)
+			return c, err
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list nick history: %w", err)
+	}
+
+	return history, nil
+}
+
+// CheckNicks reports, for those of nicks that account id could not claim
+// given cooldown, domain.NickCheckTaken or domain.NickCheckCooldown, in one
+// query. Keys are lowercase.
+func (r *Repository) CheckNicks(ctx context.Context, id uuid.UUID, nicks []string, cooldown time.Duration) (map[string]domain.NickCheckReason, error) {
+	const q = `
+		SELECT key, reason
+		FROM (
+			SELECT n.key,
+			       CASE
+			           WHEN EXISTS (
+			               SELECT 1 FROM accounts a WHERE a.nick_key = n.key AND a.id <> $2
+			           ) THEN 'taken'
+			           WHEN EXISTS (
+			               SELECT 1
+			               FROM nick_history h
+			               WHERE lower(h.nick) = n.key
+			                 AND h.account_id <> $2
+			                 AND h.released_at > NOW() - make_interval(secs => $3)
+			           ) THEN 'cooldown'
+			       END AS reason
+			FROM unnest($1::text[]) AS n(key)
+		) c
+		WHERE reason IS NOT NULL
+	`
+
+	keys := make([]string, len(nicks))
+	for i, nick := range nicks {
+		keys[i] = strings.ToLower(nick)
+	}
+
+	unavailable := make(map[string]domain.NickCheckReason)
+	err := r.read(ctx, "check_nicks", func(db *pgxpool.Pool) error {
+		rows, err := db.Query(ctx, q, keys, id, cooldown.Seconds())
+		if err != nil {
+			return err
+		}
+
+		clear(unavailable)
+		var (
+			key    string
+			reason domain.NickCheckReason
+		)
+		_, err = pgx.ForEachRow(rows, []any{&key, &reason}, func() error {
+			unavailable[key] = reason
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("check nicks: %w", err)
+	}
+
+	return unavailable, nil
+}
+
+// claimNick serializes claims and releases of nick and release, which is
+// empty when nothing is released, and returns domain.ErrNickInCooldown when
+// another account released nick less than cooldown ago. The advisory locks
+// close the window in which a claim could miss a release committed
+// concurrently. Both ignore the case of the nicks.
+func claimNick(ctx context.Context, tx pgx.Tx, id uuid.UUID, nick, release string, cooldown time.Duration) error {
+	const (
+		lock = `
+			SELECT pg_advisory_xact_lock(hashtext(n))
+			FROM unnest($1::text[]) AS n
+			ORDER BY n
+		`
+		inCooldown = `
+			SELECT EXISTS (
+				SELECT 1
+				FROM nick_history
+				WHERE lower(nick) = lower($1)
+				  AND account_id <> $2
+				  AND released_at > NOW() - make_interval(secs => $3)
+			)
+		`
+	)
+
+	nicks := []string{strings.ToLower(nick)}
+	if release != "" {
+		nicks = append(nicks, strings.ToLower(release))
+	}
+	if _, err := tx.Exec(ctx, lock, nicks); err != nil {
+		return fmt.Errorf("lock nicks: %w", err)
+	}
+
+	if cooldown <= 0 {
+		return nil
+	}
+
+	var blocked bool
+	if err := tx.QueryRow(ctx, inCooldown, nick, id, cooldown.Seconds()).Scan(&blocked); err != nil {
+		return fmt.Errorf("check nick cooldown: %w", err)
+	}
+	if blocked {
+		return domain.ErrNickInCooldown
+	}
+
+	return nil
+}
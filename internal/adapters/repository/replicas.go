@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+type ReplicaConfig struct {
+	Pools []*pgxpool.Pool
+	// MaxWait bounds how long a read carrying a consistency token waits for
+	// a lagging replica before it is served by the primary instead.
+	MaxWait time.Duration
+}
+
+type replica struct {
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// read runs fn against a healthy replica, or against the primary when no
+// replica is available. A consistency token in ctx restricts replicas to
+// those that have replayed the token's WAL position; a lagging replica is
+// skipped for this read. A replica that fails with anything other than
+// pgx.ErrNoRows is marked unhealthy and the read is retried on the primary.
+func (r *Repository) read(ctx context.Context, method string, fn func(*pgxpool.Pool) error) error {
+	var lsn string
+	if token := domain.ConsistencyTokenFrom(ctx); token != "" {
+		var err error
+		if lsn, err = decodeConsistencyToken(token); err != nil {
+			return err
+		}
+	}
+
+	if rep := r.pickReplica(); rep != nil {
+		caughtUp := true
+		var err error
+		if lsn != "" {
+			caughtUp, err = r.waitForLSN(ctx, rep, lsn)
+		}
+
+		if err == nil && caughtUp {
+			err = r.observeRead(method, telemetry.DBRoleReplica, func() error { return fn(rep.pool) })
+			if err == nil || errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			rep.healthy.Store(false)
+		}
+	}
+
+	return r.observeRead(method, telemetry.DBRolePrimary, func() error { return fn(r.pool) })
+}
+
+func (r *Repository) observeRead(method, role string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+
+	status := "ok"
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		status = "not_found"
+	case err != nil:
+		status = "error"
+	}
+	r.metrics.ObserveDB(method, role, status, time.Since(start))
+
+	return err
+}
+
+// pickReplica returns the next healthy replica in round-robin order, or nil.
+func (r *Repository) pickReplica() *replica {
+	n := uint64(len(r.replicas))
+	for range n {
+		rep := r.replicas[r.next.Add(1)%n]
+		if rep.healthy.Load() {
+			return rep
+		}
+	}
+
+	return nil
+}
+
+// MonitorReplicas pings every replica each interval and updates its health
+// until ctx is done. Unhealthy replicas rejoin the rotation once a ping
+// succeeds again.
+func (r *Repository) MonitorReplicas(ctx context.Context, interval time.Duration) {
+	if len(r.replicas) == 0 || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.CheckReplicas(ctx, interval)
+		}
+	}
+}
+
+// CheckReplicas pings every replica, each for at most timeout if positive,
+// and takes those that fail out of rotation until a later check succeeds.
+// It returns the ping error of each replica in the order of
+// ReplicaConfig.Pools, nil for healthy ones.
+func (r *Repository) CheckReplicas(ctx context.Context, timeout time.Duration) []error {
+	errs := make([]error, len(r.replicas))
+	for i, rep := range r.replicas {
+		pingCtx, cancel := context.WithCancel(ctx)
+		if timeout > 0 {
+			pingCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		errs[i] = rep.pool.Ping(pingCtx)
+		cancel()
+		rep.healthy.Store(errs[i] == nil)
+	}
+
+	return errs
+}
+
+// HealthyReplicas returns how many replicas are currently in rotation.
+func (r *Repository) HealthyReplicas() int {
+	healthy := 0
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			healthy++
+		}
+	}
+
+	return healthy
+}
@@ -2,54 +2,108 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/kvetinski/account/internal/domain"
 	"github.com/kvetinski/account/internal/telemetry"
 )
 
+const uniqueViolationCode = "23505"
+
 type Repository struct {
-	db      *sql.DB
-	metrics *telemetry.Metrics
+	pool           *pgxpool.Pool
+	replicas       []*replica
+	replicaMaxWait time.Duration
+	next           atomic.Uint64
+	metrics        *telemetry.Metrics
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return NewWithMetrics(pool, nil)
 }
 
-func New(db *sql.DB) *Repository {
-	return NewWithMetrics(db, nil)
+func NewWithMetrics(pool *pgxpool.Pool, metrics *telemetry.Metrics) *Repository {
+	return NewWithReplicas(pool, ReplicaConfig{}, metrics)
 }
 
-func NewWithMetrics(db *sql.DB, metrics *telemetry.Metrics) *Repository {
-	return &Repository{
-		db:      db,
-		metrics: metrics,
+// NewWithReplicas returns a Repository that sends mutations to primary and
+// spreads reads over replicas, falling back to primary when none is healthy.
+func NewWithReplicas(primary *pgxpool.Pool, replicas ReplicaConfig, metrics *telemetry.Metrics) *Repository {
+	r := &Repository{
+		pool:           primary,
+		replicaMaxWait: replicas.MaxWait,
+		metrics:        metrics,
 	}
+	for _, pool := range replicas.Pools {
+		rep := &replica{pool: pool}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+
+	return r
+}
+
+// accountColumns is the column list scanned by scanAccount.
+const accountColumns = `id, nick, phone, phone_type, phone_flagged, status, status_reason, display_name, bio, avatar_url, avatar_thumbnail_url, locale, time_zone, created_at, updated_at, deleted_at`
+
+// prefixedAccountColumns is accountColumns qualified with the alias "a", for
+// queries that join accounts.
+const prefixedAccountColumns = `a.id, a.nick, a.phone, a.phone_type, a.phone_flagged, a.status, a.status_reason, a.display_name, a.bio, a.avatar_url, a.avatar_thumbnail_url, a.locale, a.time_zone, a.created_at, a.updated_at, a.deleted_at`
+
+func scanAccount(row pgx.Row) (domain.Account, error) {
+	var a domain.Account
+	err := row.Scan(
+		&a.ID, &a.Nick, &a.Phone, &a.PhoneType, &a.PhoneFlagged, &a.Status, &a.StatusReason,
+		&a.Profile.DisplayName, &a.Profile.Bio, &a.Profile.AvatarURL, &a.Profile.AvatarThumbnailURL, &a.Profile.Locale, &a.Profile.TimeZone,
+		&a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+	)
+	return a, err
 }
 
-func (r *Repository) Create(ctx context.Context, id uuid.UUID, nick, phone string) (domain.Account, error) {
+// Create inserts the account unless nick was released by another account
+// less than cooldown ago, in which case it returns domain.ErrNickInCooldown.
+func (r *Repository) Create(ctx context.Context, id uuid.UUID, nick string, phone domain.Phone, cooldown time.Duration) (domain.Account, error) {
 	start := time.Now()
 	status := "ok"
 	defer func() {
-		r.metrics.ObserveDB("create", status, time.Since(start))
+		r.metrics.ObserveDB("create", telemetry.DBRolePrimary, status, time.Since(start))
 	}()
 
 	const q = `
-		INSERT INTO accounts (id, nick, phone)
-		VALUES ($1, $2, $3)
-		RETURNING id, nick, phone, created_at, updated_at, deleted_at
-	`
+		INSERT INTO accounts (id, nick, phone, phone_type, phone_flagged)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + accountColumns
 
 	var a domain.Account
-	if err := r.db.QueryRowContext(ctx, q, id, nick, phone).Scan(&a.ID, &a.Nick, &a.Phone, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt); err != nil {
-		var pqErr *pq.Error
-		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := claimNick(ctx, tx, id, nick, "", cooldown); err != nil {
+			return err
+		}
+
+		var err error
+		a, err = scanAccount(tx.QueryRow(ctx, q, id, nick, phone.Number, phone.Type, phone.Flagged))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNickInCooldown) {
+			status = "conflict"
+			return domain.Account{}, err
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
 			status = "conflict"
-			switch pqErr.Constraint {
-			case "accounts_nick_key":
+			switch pgErr.ConstraintName {
+			case "accounts_nick_key_key":
 				return domain.Account{}, domain.ErrNickAlreadyExists
 			case "accounts_phone_key":
 				return domain.Account{}, domain.ErrPhoneAlreadyExists
@@ -66,56 +120,100 @@ func (r *Repository) Create(ctx context.Context, id uuid.UUID, nick, phone strin
 }
 
 func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (domain.Account, error) {
-	start := time.Now()
-	status := "ok"
-	defer func() {
-		r.metrics.ObserveDB("get_by_id", status, time.Since(start))
-	}()
-
 	const q = `
-		SELECT id, nick, phone, created_at, updated_at, deleted_at
+		SELECT ` + accountColumns + `
 		FROM accounts
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var a domain.Account
-	if err := r.db.QueryRowContext(ctx, q, id).Scan(&a.ID, &a.Nick, &a.Phone, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			status = "not_found"
+	err := r.read(ctx, "get_by_id", func(db *pgxpool.Pool) error {
+		var err error
+		a, err = scanAccount(db.QueryRow(ctx, q, id))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return domain.Account{}, domain.ErrAccountNotFound
 		}
 
-		status = "error"
 		return domain.Account{}, fmt.Errorf("get account: %w", err)
 	}
 
 	return a, nil
 }
 
-func (r *Repository) UpdateNick(ctx context.Context, id uuid.UUID, nick string) (domain.Account, error) {
+// UpdateNick changes the nick of an active account and records the previous
+// one in its nick history. It returns domain.ErrAccountNotActive for suspended
+// or banned accounts and domain.ErrNickInCooldown when another account
+// released nick less than cooldown ago.
+func (r *Repository) UpdateNick(ctx context.Context, id uuid.UUID, nick string, cooldown time.Duration) (domain.Account, error) {
 	start := time.Now()
 	status := "ok"
 	defer func() {
-		r.metrics.ObserveDB("update_nick", status, time.Since(start))
+		r.metrics.ObserveDB("update_nick", telemetry.DBRolePrimary, status, time.Since(start))
 	}()
 
-	const q = `
-		UPDATE accounts
-		SET nick = $2,
-		    updated_at = NOW()
-		WHERE id = $1 AND deleted_at IS NULL
-		RETURNING id, nick, phone, created_at, updated_at, deleted_at
-	`
+	const (
+		selectCurrent = `
+			SELECT nick, status
+			FROM accounts
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		`
+		update = `
+			UPDATE accounts
+			SET nick = $2,
+			    updated_at = NOW()
+			WHERE id = $1
+			RETURNING ` + accountColumns
+		recordHistory = `
+			INSERT INTO nick_history (account_id, nick, released_at)
+			VALUES ($1, $2, NOW())
+		`
+	)
 
 	var a domain.Account
-	if err := r.db.QueryRowContext(ctx, q, id, nick).Scan(&a.ID, &a.Nick, &a.Phone, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var (
+			current       string
+			currentStatus domain.Status
+		)
+		if err := tx.QueryRow(ctx, selectCurrent, id).Scan(&current, &currentStatus); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrAccountNotFound
+			}
+			return err
+		}
+		if currentStatus != domain.StatusActive {
+			return domain.ErrAccountNotActive
+		}
+		// A change of case keeps the nick and is not recorded.
+		if !strings.EqualFold(current, nick) {
+			if err := claimNick(ctx, tx, id, nick, current, cooldown); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, recordHistory, id, current); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		a, err = scanAccount(tx.QueryRow(ctx, update, id, nick))
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccountNotFound), errors.Is(err, domain.ErrAccountNotActive):
 			status = "not_found"
-			return domain.Account{}, domain.ErrAccountNotFound
+			return domain.Account{}, err
+		case errors.Is(err, domain.ErrNickInCooldown):
+			status = "conflict"
+			return domain.Account{}, err
 		}
 
-		var pqErr *pq.Error
-		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
 			status = "conflict"
 			return domain.Account{}, domain.ErrNickAlreadyExists
 		}
@@ -127,36 +225,120 @@ func (r *Repository) UpdateNick(ctx context.Context, id uuid.UUID, nick string)
 	return a, nil
 }
 
-func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
+// UpdateProfile applies the set fields of update to an active account. It
+// returns domain.ErrAccountNotActive for suspended or banned accounts.
+func (r *Repository) UpdateProfile(ctx context.Context, id uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
 	start := time.Now()
 	status := "ok"
 	defer func() {
-		r.metrics.ObserveDB("delete", status, time.Since(start))
+		r.metrics.ObserveDB("update_profile", telemetry.DBRolePrimary, status, time.Since(start))
 	}()
 
 	const q = `
 		UPDATE accounts
-		SET deleted_at = NOW(),
+		SET display_name = COALESCE($2, display_name),
+		    bio = COALESCE($3, bio),
+		    avatar_url = COALESCE($4, avatar_url),
+		    avatar_thumbnail_url = COALESCE($5, avatar_thumbnail_url),
+		    locale = COALESCE($6, locale),
+		    time_zone = COALESCE($7, time_zone),
 		    updated_at = NOW()
-		WHERE id = $1 AND deleted_at IS NULL
-	`
+		WHERE id = $1 AND deleted_at IS NULL AND status = 'active'
+		RETURNING ` + accountColumns
 
-	res, err := r.db.ExecContext(ctx, q, id)
+	a, err := scanAccount(r.pool.QueryRow(ctx, q, id, update.DisplayName, update.Bio, update.AvatarURL, update.AvatarThumbnailURL, update.Locale, update.TimeZone))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			status = "not_found"
+			return domain.Account{}, r.missingOr(ctx, id, domain.ErrAccountNotActive)
+		}
+
 		status = "error"
-		return fmt.Errorf("delete account: %w", err)
+		return domain.Account{}, fmt.Errorf("update profile: %w", err)
 	}
 
-	rows, err := res.RowsAffected()
+	return a, nil
+}
+
+// UpdateStatus moves the account to status to, provided its current status
+// is one of from. It returns domain.ErrInvalidStatusTransition when the
+// account exists in any other status.
+func (r *Repository) UpdateStatus(ctx context.Context, id uuid.UUID, to domain.Status, reason domain.StatusReason, from ...domain.Status) (domain.Account, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("update_status", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		UPDATE accounts
+		SET status = $2,
+		    status_reason = $3,
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND status = ANY($4)
+		RETURNING ` + accountColumns
+
+	statuses := make([]string, len(from))
+	for i, s := range from {
+		statuses[i] = string(s)
+	}
+
+	a, err := scanAccount(r.pool.QueryRow(ctx, q, id, string(to), string(reason), statuses))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			status = "not_found"
+			return domain.Account{}, r.missingOr(ctx, id, domain.ErrInvalidStatusTransition)
+		}
+
 		status = "error"
-		return fmt.Errorf("delete account rows affected: %w", err)
+		return domain.Account{}, fmt.Errorf("update status: %w", err)
 	}
 
-	if rows == 0 {
+	return a, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("delete", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		UPDATE accounts
+		SET status = 'deleted',
+		    deleted_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	tag, err := r.pool.Exec(ctx, q, id)
+	if err != nil {
+		status = "error"
+		return fmt.Errorf("delete account: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
 		status = "not_found"
 		return domain.ErrAccountNotFound
 	}
 
 	return nil
 }
+
+// missingOr tells apart a conditional update that matched no row because the
+// account does not exist from one whose condition did not hold, in which case
+// it returns conditionErr.
+func (r *Repository) missingOr(ctx context.Context, id uuid.UUID, conditionErr error) error {
+	const q = `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = $1 AND deleted_at IS NULL)`
+
+	var exists bool
+	if err := r.pool.QueryRow(ctx, q, id).Scan(&exists); err != nil {
+		return fmt.Errorf("check account exists: %w", err)
+	}
+	if !exists {
+		return domain.ErrAccountNotFound
+	}
+
+	return conditionErr
+}
@@ -0,0 +1,49 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+func BenchmarkRepositoryCreate(b *testing.B) {
+	s := newIntegrationSuite(b)
+	defer s.close()
+	s.resetSchema(b)
+
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := range b.N {
+		if _, err := s.repo.Create(ctx, uuid.New(), fmt.Sprintf("@bench_%d", i), domain.Phone{Number: fmt.Sprintf("+1555%08d", i)}, 0); err != nil {
+			b.Fatalf("Create failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkRepositoryGetByID(b *testing.B) {
+	s := newIntegrationSuite(b)
+	defer s.close()
+	s.resetSchema(b)
+
+	ctx := context.Background()
+	acc, err := s.repo.Create(ctx, uuid.New(), "@bench_get", domain.Phone{Number: "+15550009999"}, 0)
+	if err != nil {
+		b.Fatalf("Create failed: %v", err)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := s.repo.GetByID(ctx, acc.ID); err != nil {
+				b.Errorf("GetByID failed: %v", err)
+				return
+			}
+		}
+	})
+}
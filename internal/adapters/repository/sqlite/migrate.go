@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"sort"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/kvetinski/account/migrations"
+)
+
+// Open opens the SQLite database at path with the pragmas the repository
+// relies on: WAL for concurrent readers, a busy timeout instead of immediate
+// SQLITE_BUSY errors, and enforced foreign keys.
+func Open(path string) (*sql.DB, error) {
+	// SQLite decodes the path of a file: URI, so "?", "#" and "%" in path
+	// must be escaped to reach the file name.
+	dsn := url.URL{
+		Scheme: "file",
+		Opaque: (&url.URL{Path: path}).EscapedPath(),
+		RawQuery: url.Values{
+			"_pragma": []string{"busy_timeout(5000)", "journal_mode(WAL)", "foreign_keys(1)"},
+		}.Encode(),
+	}
+
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY churn.
+	db.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+// Migrate applies the embedded SQLite migrations that have not been applied
+// yet, recording each version in schema_migrations.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	const createVersions = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY
+		)
+	`
+	if _, err := db.ExecContext(ctx, createVersions); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	names, err := fs.Glob(migrations.SQLite, "sqlite/*.up.sql")
+	if err != nil {
+		return fmt.Errorf("list migrations: %w", err)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version, _, _ := strings.Cut(strings.TrimPrefix(name, "sqlite/"), "_")
+		if err = applyMigration(ctx, db, version, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// preconditions are checked before the migration of the same version is
+// applied, for failures that the migration itself would report poorly.
+var preconditions = map[string]func(ctx context.Context, tx *sql.Tx) error{
+	"20261018170000": checkNickCaseCollisions,
+}
+
+// checkNickCaseCollisions lists the nicks that differ only in case, which the
+// case-insensitive unique index cannot be created over.
+func checkNickCaseCollisions(ctx context.Context, tx *sql.Tx) error {
+	const q = `
+		SELECT lower(nick), group_concat(nick || ' (' || id || ')', ', ')
+		FROM (SELECT nick, id FROM accounts ORDER BY created_at)
+		GROUP BY lower(nick)
+		HAVING COUNT(*) > 1
+		ORDER BY lower(nick)
+	`
+
+	rows, err := tx.QueryContext(ctx, q)
+	if err != nil {
+		return fmt.Errorf("find nick case collisions: %w", err)
+	}
+	defer rows.Close()
+
+	var collisions []string
+	for rows.Next() {
+		var key, holders string
+		if err = rows.Scan(&key, &holders); err != nil {
+			return fmt.Errorf("scan nick case collision: %w", err)
+		}
+		collisions = append(collisions, key+": "+holders)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("find nick case collisions: %w", err)
+	}
+
+	if len(collisions) > 0 {
+		return fmt.Errorf("nicks differing only in case must be resolved before making nicks case-insensitive: %s", strings.Join(collisions, "; "))
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, version, name string) error {
+	var applied bool
+	const q = `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = ?)`
+	if err := db.QueryRowContext(ctx, q, version).Scan(&applied); err != nil {
+		return fmt.Errorf("check migration %s: %w", version, err)
+	}
+	if applied {
+		return nil
+	}
+
+	body, err := fs.ReadFile(migrations.SQLite, name)
+	if err != nil {
+		return fmt.Errorf("read migration %s: %w", version, err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration %s: %w", version, err)
+	}
+	defer tx.Rollback()
+
+	if check, ok := preconditions[version]; ok {
+		if err = check(ctx, tx); err != nil {
+			return fmt.Errorf("apply migration %s: %w", version, err)
+		}
+	}
+	if _, err = tx.ExecContext(ctx, string(body)); err != nil {
+		return fmt.Errorf("apply migration %s: %w", version, err)
+	}
+	if _, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+		return fmt.Errorf("record migration %s: %w", version, err)
+	}
+
+	return tx.Commit()
+}
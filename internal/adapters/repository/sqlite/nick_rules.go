@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+func (r *Repository) ListNickRules(ctx context.Context) ([]domain.NickRule, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("list_nick_rules", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		SELECT id, kind, value, reason, created_at
+		FROM nick_rules
+		ORDER BY kind, value
+	`
+
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		status = "error"
+		return nil, fmt.Errorf("list nick rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []domain.NickRule
+	for rows.Next() {
+		var rule domain.NickRule
+		if err = rows.Scan(&rule.ID, &rule.Kind, &rule.Value, &rule.Reason, &rule.CreatedAt); err != nil {
+			status = "error"
+			return nil, fmt.Errorf("scan nick rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err = rows.Err(); err != nil {
+		status = "error"
+		return nil, fmt.Errorf("list nick rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// AddNickRule returns domain.ErrNickRuleExists if a rule of the same kind and
+// value exists.
+func (r *Repository) AddNickRule(ctx context.Context, rule domain.NickRule) (domain.NickRule, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("add_nick_rule", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		INSERT INTO nick_rules (id, kind, value, reason, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	rule.CreatedAt = r.now()
+	if _, err := r.db.ExecContext(ctx, q, rule.ID, string(rule.Kind), rule.Value, rule.Reason, rule.CreatedAt); err != nil {
+		if _, ok := uniqueViolation(err); ok {
+			status = "conflict"
+			return domain.NickRule{}, domain.ErrNickRuleExists
+		}
+
+		status = "error"
+		return domain.NickRule{}, fmt.Errorf("add nick rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *Repository) DeleteNickRule(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("delete_nick_rule", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM nick_rules WHERE id = ?`, id)
+	if err != nil {
+		status = "error"
+		return fmt.Errorf("delete nick rule: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		status = "error"
+		return fmt.Errorf("delete nick rule rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		status = "not_found"
+		return domain.ErrNickRuleNotFound
+	}
+
+	return nil
+}
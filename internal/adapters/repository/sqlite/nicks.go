@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+// GetByNick returns the account currently holding nick or, failing that, the
+// live account that released it most recently within redirectPeriod. Nicks
+// are compared ignoring case.
+func (r *Repository) GetByNick(ctx context.Context, nick string, redirectPeriod time.Duration) (domain.Account, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("get_by_nick", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const (
+		current = `
+			SELECT ` + accountColumns + `
+			FROM accounts
+			WHERE nick_key = lower(?) AND deleted_at IS NULL
+		`
+		redirect = `
+			SELECT ` + accountColumns + `
+			FROM accounts
+			WHERE id = (
+				SELECT h.account_id
+				FROM nick_history h
+				JOIN accounts a ON a.id = h.account_id
+				WHERE lower(h.nick) = lower(?) AND h.released_at > ? AND a.deleted_at IS NULL
+				ORDER BY h.released_at DESC, h.id DESC
+				LIMIT 1
+			)
+		`
+	)
+
+	a, err := scanAccount(r.db.QueryRowContext(ctx, current, nick))
+	if errors.Is(err, sql.ErrNoRows) {
+		a, err = scanAccount(r.db.QueryRowContext(ctx, redirect, nick, r.now().Add(-redirectPeriod)))
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			status = "not_found"
+			return domain.Account{}, domain.ErrAccountNotFound
+		}
+
+		status = "error"
+		return domain.Account{}, fmt.Errorf("get account by nick: %w", err)
+	}
+
+	return a, nil
+}
+
+// ListNickHistory returns the nicks the account has released, newest first.
+func (r *Repository) ListNickHistory(ctx context.Context, id uuid.UUID) ([]domain.NickChange, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("list_nick_history", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		SELECT nick, released_at
+		FROM nick_history
+		WHERE account_id = ?
+		ORDER BY released_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, id)
+	if err != nil {
+		status = "error"
+		return nil, fmt.Errorf("list nick history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []domain.NickChange
+	for rows.Next() {
+		var c domain.NickChange
+		if err = rows.Scan(&c.Nick, &c.ReleasedAt); err != nil {
+			status = "error"
+			return nil, fmt.Errorf("scan nick history: %w", err)
+		}
+		history = append(history, c)
+	}
+	if err = rows.Err(); err != nil {
+		status = "error"
+		return nil, fmt.Errorf("list nick history: %w", err)
+	}
+
+	return history, nil
+}
+
+// CheckNicks reports, for those of nicks that account id could not claim
+// given cooldown, domain.NickCheckTaken or domain.NickCheckCooldown, in one
+// query. Keys are lowercase.
+func (r *Repository) CheckNicks(ctx context.Context, id uuid.UUID, nicks []string, cooldown time.Duration) (map[string]domain.NickCheckReason, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("check_nicks", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		SELECT key, reason
+		FROM (
+			SELECT lower(n.value) AS key,
+			       CASE
+			           WHEN EXISTS (
+			               SELECT 1 FROM accounts a WHERE a.nick_key = lower(n.value) AND a.id <> ?
+			           ) THEN 'taken'
+			           WHEN EXISTS (
+			               SELECT 1
+			               FROM nick_history h
+			               WHERE lower(h.nick) = lower(n.value) AND h.account_id <> ? AND h.released_at > ?
+			           ) THEN 'cooldown'
+			       END AS reason
+			FROM json_each(?) AS n
+		)
+		WHERE reason IS NOT NULL
+	`
+
+	list, err := json.Marshal(nicks)
+	if err != nil {
+		status = "error"
+		return nil, fmt.Errorf("encode nicks: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, q, id, id, r.now().Add(-cooldown), string(list))
+	if err != nil {
+		status = "error"
+		return nil, fmt.Errorf("check nicks: %w", err)
+	}
+	defer rows.Close()
+
+	unavailable := make(map[string]domain.NickCheckReason)
+	for rows.Next() {
+		var (
+			key    string
+			reason domain.NickCheckReason
+		)
+		if err = rows.Scan(&key, &reason); err != nil {
+			status = "error"
+			return nil, fmt.Errorf("scan nick check: %w", err)
+		}
+		unavailable[key] = reason
+	}
+	if err = rows.Err(); err != nil {
+		status = "error"
+		return nil, fmt.Errorf("check nicks: %w", err)
+	}
+
+	return unavailable, nil
+}
+
+// checkNickCooldown returns domain.ErrNickInCooldown when another account
+// released nick less than cooldown before now. SQLite runs one writer at a
+// time, so the check cannot race with a concurrent release.
+func checkNickCooldown(ctx context.Context, tx *sql.Tx, id uuid.UUID, nick string, cooldown time.Duration, now time.Time) error {
+	if cooldown <= 0 {
+		return nil
+	}
+
+	const q = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM nick_history
+			WHERE lower(nick) = lower(?) AND account_id <> ? AND released_at > ?
+		)
+	`
+
+	var blocked bool
+	if err := tx.QueryRowContext(ctx, q, nick, id, now.Add(-cooldown)).Scan(&blocked); err != nil {
+		return fmt.Errorf("check nick cooldown: %w", err)
+	}
+	if blocked {
+		return domain.ErrNickInCooldown
+	}
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+type Repository struct {
+	db      *sql.DB
+	metrics *telemetry.Metrics
+	now     func() time.Time
+}
+
+func New(db *sql.DB) *Repository {
+	return NewWithMetrics(db, nil)
+}
+
+func NewWithMetrics(db *sql.DB, metrics *telemetry.Metrics) *Repository {
+	return &Repository{
+		db:      db,
+		metrics: metrics,
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// accountColumns is the column list scanned by scanAccount.
+const accountColumns = `id, nick, phone, phone_type, phone_flagged, status, status_reason, display_name, bio, avatar_url, avatar_thumbnail_url, locale, time_zone, created_at, updated_at, deleted_at`
+
+func scanAccount(row *sql.Row) (domain.Account, error) {
+	var a domain.Account
+	err := row.Scan(
+		&a.ID, &a.Nick, &a.Phone, &a.PhoneType, &a.PhoneFlagged, &a.Status, &a.StatusReason,
+		&a.Profile.DisplayName, &a.Profile.Bio, &a.Profile.AvatarURL, &a.Profile.AvatarThumbnailURL, &a.Profile.Locale, &a.Profile.TimeZone,
+		&a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+	)
+	return a, err
+}
+
+// Create inserts the account unless nick was released by another account
+// less than cooldown ago, in which case it returns domain.ErrNickInCooldown.
+func (r *Repository) Create(ctx context.Context, id uuid.UUID, nick string, phone domain.Phone, cooldown time.Duration) (domain.Account, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("create", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		INSERT INTO accounts (id, nick, phone, phone_type, phone_flagged, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING ` + accountColumns
+
+	now := r.now()
+	var a domain.Account
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := checkNickCooldown(ctx, tx, id, nick, cooldown, now); err != nil {
+			return err
+		}
+
+		var err error
+		a, err = scanAccount(tx.QueryRowContext(ctx, q, id, nick, phone.Number, phone.Type, phone.Flagged, now, now))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNickInCooldown) {
+			status = "conflict"
+			return domain.Account{}, err
+		}
+
+		if column, ok := uniqueViolation(err); ok {
+			status = "conflict"
+			switch column {
+			case "accounts.phone":
+				return domain.Account{}, domain.ErrPhoneAlreadyExists
+			default:
+				return domain.Account{}, domain.ErrNickAlreadyExists
+			}
+		}
+
+		status = "error"
+		return domain.Account{}, fmt.Errorf("create account: %w", err)
+	}
+
+	return a, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (domain.Account, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("get_by_id", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		SELECT ` + accountColumns + `
+		FROM accounts
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	a, err := scanAccount(r.db.QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			status = "not_found"
+			return domain.Account{}, domain.ErrAccountNotFound
+		}
+
+		status = "error"
+		return domain.Account{}, fmt.Errorf("get account: %w", err)
+	}
+
+	return a, nil
+}
+
+// UpdateNick changes the nick of an active account and records the previous
+// one in its nick history. It returns domain.ErrAccountNotActive for suspended
+// or banned accounts and domain.ErrNickInCooldown when another account
+// released nick less than cooldown ago.
+func (r *Repository) UpdateNick(ctx context.Context, id uuid.UUID, nick string, cooldown time.Duration) (domain.Account, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("update_nick", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const (
+		selectCurrent = `
+			SELECT nick, status
+			FROM accounts
+			WHERE id = ? AND deleted_at IS NULL
+		`
+		update = `
+			UPDATE accounts
+			SET nick = ?,
+			    updated_at = ?
+			WHERE id = ?
+			RETURNING ` + accountColumns
+		recordHistory = `
+			INSERT INTO nick_history (account_id, nick, released_at)
+			VALUES (?, ?, ?)
+		`
+	)
+
+	now := r.now()
+	var a domain.Account
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		var (
+			current       string
+			currentStatus domain.Status
+		)
+		if err := tx.QueryRowContext(ctx, selectCurrent, id).Scan(&current, &currentStatus); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrAccountNotFound
+			}
+			return err
+		}
+		if currentStatus != domain.StatusActive {
+			return domain.ErrAccountNotActive
+		}
+
+		// A change of case keeps the nick and is not recorded.
+		if !strings.EqualFold(current, nick) {
+			if err := checkNickCooldown(ctx, tx, id, nick, cooldown, now); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, recordHistory, id, current, now); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		a, err = scanAccount(tx.QueryRowContext(ctx, update, nick, now, id))
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAccountNotFound), errors.Is(err, domain.ErrAccountNotActive):
+			status = "not_found"
+			return domain.Account{}, err
+		case errors.Is(err, domain.ErrNickInCooldown):
+			status = "conflict"
+			return domain.Account{}, err
+		}
+
+		if _, ok := uniqueViolation(err); ok {
+			status = "conflict"
+			return domain.Account{}, domain.ErrNickAlreadyExists
+		}
+
+		status = "error"
+		return domain.Account{}, fmt.Errorf("update nick: %w", err)
+	}
+
+	return a, nil
+}
+
+// UpdateProfile applies the set fields of update to an active account. It
+// returns domain.ErrAccountNotActive for suspended or banned accounts.
+func (r *Repository) UpdateProfile(ctx context.Context, id uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("update_profile", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		UPDATE accounts
+		SET display_name = COALESCE(?, display_name),
+		    bio = COALESCE(?, bio),
+		    avatar_url = COALESCE(?, avatar_url),
+		    avatar_thumbnail_url = COALESCE(?, avatar_thumbnail_url),
+		    locale = COALESCE(?, locale),
+		    time_zone = COALESCE(?, time_zone),
+		    updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL AND status = 'active'
+		RETURNING ` + accountColumns
+
+	a, err := scanAccount(r.db.QueryRowContext(ctx, q, update.DisplayName, update.Bio, update.AvatarURL, update.AvatarThumbnailURL, update.Locale, update.TimeZone, r.now(), id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			status = "not_found"
+			return domain.Account{}, r.missingOr(ctx, id, domain.ErrAccountNotActive)
+		}
+
+		status = "error"
+		return domain.Account{}, fmt.Errorf("update profile: %w", err)
+	}
+
+	return a, nil
+}
+
+// UpdateStatus moves the account to status to, provided its current status
+// is one of from. It returns domain.ErrInvalidStatusTransition when the
+// account exists in any other status.
+func (r *Repository) UpdateStatus(ctx context.Context, id uuid.UUID, to domain.Status, reason domain.StatusReason, from ...domain.Status) (domain.Account, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("update_status", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	if len(from) == 0 {
+		status = "not_found"
+		return domain.Account{}, r.missingOr(ctx, id, domain.ErrInvalidStatusTransition)
+	}
+
+	q := `
+		UPDATE accounts
+		SET status = ?,
+		    status_reason = ?,
+		    updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL AND status IN (?` + strings.Repeat(", ?", len(from)-1) + `)
+		RETURNING ` + accountColumns
+
+	args := []any{string(to), string(reason), r.now(), id}
+	for _, s := range from {
+		args = append(args, string(s))
+	}
+
+	a, err := scanAccount(r.db.QueryRowContext(ctx, q, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			status = "not_found"
+			return domain.Account{}, r.missingOr(ctx, id, domain.ErrInvalidStatusTransition)
+		}
+
+		status = "error"
+		return domain.Account{}, fmt.Errorf("update status: %w", err)
+	}
+
+	return a, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("delete", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		UPDATE accounts
+		SET status = 'deleted',
+		    deleted_at = ?,
+		    updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	now := r.now()
+	res, err := r.db.ExecContext(ctx, q, now, now, id)
+	if err != nil {
+		status = "error"
+		return fmt.Errorf("delete account: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		status = "error"
+		return fmt.Errorf("delete account rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		status = "not_found"
+		return domain.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+// inTx runs fn in a transaction, committing if it returns nil.
+func (r *Repository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// missingOr tells apart a conditional update that matched no row because the
+// account does not exist from one whose condition did not hold, in which case
+// it returns conditionErr.
+func (r *Repository) missingOr(ctx context.Context, id uuid.UUID, conditionErr error) error {
+	const q = `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = ? AND deleted_at IS NULL)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, q, id).Scan(&exists); err != nil {
+		return fmt.Errorf("check account exists: %w", err)
+	}
+	if !exists {
+		return domain.ErrAccountNotFound
+	}
+
+	return conditionErr
+}
+
+// ConsistencyToken returns an empty token: SQLite has no replicas, so every
+// read already observes every committed write.
+func (r *Repository) ConsistencyToken(context.Context) (string, error) {
+	return "", nil
+}
+
+// uniqueViolation reports whether err is a UNIQUE constraint failure and, if
+// so, which "table.column" it was raised for. SQLite names the column rather
+// than the constraint, e.g. "UNIQUE constraint failed: accounts.nick".
+func uniqueViolation(err error) (string, bool) {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.Code() != sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		return "", false
+	}
+
+	_, column, _ := strings.Cut(sqliteErr.Error(), "UNIQUE constraint failed: ")
+	column, _, _ = strings.Cut(column, " ")
+
+	return column, true
+}
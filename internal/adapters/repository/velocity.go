@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+var _ accountsvc.VelocityStore = (*Repository)(nil)
+
+// Hit counts an event in the shared velocity_counters table, so that all
+// replicas of the service enforce one limit.
+func (r *Repository) Hit(ctx context.Context, key string, window time.Duration, now time.Time) (accountsvc.VelocityCount, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("hit_velocity", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	const q = `
+		WITH hit AS (
+			INSERT INTO velocity_counters (key, window_start, hits, expires_at)
+			VALUES ($1, $2, 1, $3)
+			ON CONFLICT (key, window_start) DO UPDATE SET hits = velocity_counters.hits + 1
+			RETURNING hits
+		)
+		SELECT
+			(SELECT hits FROM hit),
+			COALESCE((SELECT hits FROM velocity_counters WHERE key = $1 AND window_start = $4), 0)
+	`
+
+	count := accountsvc.VelocityCount{WindowStart: now.Truncate(window)}
+	// A window stops counting once the one after it has ended.
+	expiresAt := count.WindowStart.Add(2 * window)
+	err := r.pool.QueryRow(ctx, q, key, count.WindowStart, expiresAt, count.WindowStart.Add(-window)).Scan(&count.Current, &count.Previous)
+	if err != nil {
+		status = "error"
+		return accountsvc.VelocityCount{}, fmt.Errorf("hit velocity counter: %w", err)
+	}
+
+	return count, nil
+}
+
+// PruneVelocity deletes counters that expired before now.
+func (r *Repository) PruneVelocity(ctx context.Context, now time.Time) (int64, error) {
+	start := time.Now()
+	status := "ok"
+	defer func() {
+		r.metrics.ObserveDB("prune_velocity", telemetry.DBRolePrimary, status, time.Since(start))
+	}()
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM velocity_counters WHERE expires_at < $1`, now)
+	if err != nil {
+		status = "error"
+		return 0, fmt.Errorf("prune velocity counters: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
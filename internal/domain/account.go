@@ -8,18 +8,98 @@ import (
 )
 
 var (
-	ErrInvalidNick        = errors.New("invalid nick")
-	ErrInvalidPhone       = errors.New("invalid phone")
-	ErrNickAlreadyExists  = errors.New("nick already exists")
-	ErrPhoneAlreadyExists = errors.New("phone already exists")
-	ErrAccountNotFound    = errors.New("account not found")
+	ErrInvalidNick         = errors.New("invalid nick")
+	ErrInvalidPhone        = errors.New("invalid phone")
+	ErrNickAlreadyExists   = errors.New("nick already exists")
+	ErrNickInCooldown      = errors.New("nick was released recently and cannot be claimed yet")
+	ErrPhoneAlreadyExists  = errors.New("phone already exists")
+	ErrPhoneTypeNotAllowed = errors.New("phone number type is not allowed")
+	ErrAccountNotFound     = errors.New("account not found")
+
+	ErrNickReserved      = errors.New("nick is reserved")
+	ErrInvalidNickRule   = errors.New("invalid nick rule")
+	ErrNickRuleExists    = errors.New("nick rule already exists")
+	ErrNickRuleNotFound  = errors.New("nick rule not found")
+	ErrNickRulesDisabled = errors.New("nick rules are not enabled")
+	ErrNickNotAllowed    = errors.New("nick is not allowed")
+
+	ErrInvalidDisplayName = errors.New("invalid display name")
+	ErrInvalidBio         = errors.New("invalid bio")
+	ErrInvalidAvatarURL   = errors.New("invalid avatar url")
+	ErrInvalidLocale      = errors.New("invalid locale")
+	ErrInvalidTimeZone    = errors.New("invalid time zone")
+
+	ErrInvalidAvatar        = errors.New("invalid avatar image")
+	ErrAvatarTooLarge       = errors.New("avatar image too large")
+	ErrAvatarUploadDisabled = errors.New("avatar upload is not enabled")
+
+	ErrAccountNotActive        = errors.New("account is not active")
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+	ErrInvalidStatusReason     = errors.New("invalid status reason")
+
+	ErrInvalidConsistencyToken = errors.New("invalid consistency token")
+)
+
+// Status is the lifecycle state of an account. Only active accounts can be
+// modified by their owner; see accountsvc for the allowed transitions.
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusSuspended Status = "suspended"
+	StatusBanned    Status = "banned"
+	StatusDeleted   Status = "deleted"
 )
 
+// StatusReason is the machine-readable reason recorded with the last status
+// change.
+type StatusReason string
+
+const (
+	StatusReasonSpam           StatusReason = "spam"
+	StatusReasonAbuse          StatusReason = "abuse"
+	StatusReasonFraud          StatusReason = "fraud"
+	StatusReasonImpersonation  StatusReason = "impersonation"
+	StatusReasonTermsViolation StatusReason = "terms_violation"
+	StatusReasonAppealGranted  StatusReason = "appeal_granted"
+	StatusReasonOther          StatusReason = "other"
+)
+
+func (r StatusReason) Valid() bool {
+	switch r {
+	case StatusReasonSpam, StatusReasonAbuse, StatusReasonFraud, StatusReasonImpersonation,
+		StatusReasonTermsViolation, StatusReasonAppealGranted, StatusReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
 type Account struct {
-	ID        uuid.UUID  `json:"id"`
-	Nick      string     `json:"nick"`
-	Phone     string     `json:"phone"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID        uuid.UUID `json:"id"`
+	Nick      string    `json:"nick"`
+	Phone     string    `json:"phone"`
+	PhoneType PhoneType `json:"phone_type,omitempty"`
+	// PhoneFlagged marks accounts whose phone type the signup policy
+	// flags for review.
+	PhoneFlagged bool         `json:"phone_flagged,omitempty"`
+	Status       Status       `json:"status"`
+	StatusReason StatusReason `json:"status_reason,omitempty"`
+	Profile      Profile      `json:"profile"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	DeletedAt    *time.Time   `json:"deleted_at,omitempty"`
+}
+
+// NewAccount describes an account to create.
+type NewAccount struct {
+	Phone string
+	// Nick is the nick to claim. Empty generates one.
+	Nick string
+	// GenerateNickIfUnavailable generates a nick when Nick is reserved,
+	// filtered, taken or in cooldown instead of failing.
+	GenerateNickIfUnavailable bool
+	// Locale is a BCP 47 tag choosing the language of a generated nick.
+	// Empty uses the generator's default.
+	Locale string
 }
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("caller is not authenticated")
+	ErrPermissionDenied = errors.New("caller is not allowed to call this method")
+)
+
+type callerKey struct{}
+
+// Caller identifies who sent a request, as far as the transport can tell.
+// Either field may be empty.
+type Caller struct {
+	// ID is the identity the client presented, e.g. the name of the calling
+	// service.
+	ID string
+	// Authenticated is set when ID comes from a verified TLS certificate or
+	// was passed on by a trusted proxy, rather than being a bare claim of the
+	// client.
+	Authenticated bool
+	// IP is the address of the client, without port.
+	IP string
+}
+
+// IPBlock returns the IPv4 address of the caller, or the /64 of its IPv6
+// address, the smallest block usually assigned to one subscriber. Addresses
+// that do not parse are returned as they are.
+func (c Caller) IPBlock() string {
+	addr, err := netip.ParseAddr(c.IP)
+	if err != nil {
+		return c.IP
+	}
+
+	addr = addr.Unmap()
+	if addr.Is4() {
+		return addr.String()
+	}
+
+	prefix, _ := addr.Prefix(64)
+	return prefix.String()
+}
+
+// WithCaller returns a context carrying caller.
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFrom returns the caller set by WithCaller, or the zero Caller.
+func CallerFrom(ctx context.Context) Caller {
+	caller, _ := ctx.Value(callerKey{}).(Caller)
+	return caller
+}
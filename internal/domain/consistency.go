@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+type consistencyTokenKey struct{}
+
+// WithConsistencyToken returns a context carrying a read-your-writes token
+// previously issued for a mutation. Repositories that serve reads from
+// replicas use it to avoid returning data older than that mutation.
+func WithConsistencyToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, consistencyTokenKey{}, token)
+}
+
+// ConsistencyTokenFrom returns the token set by WithConsistencyToken, if any.
+func ConsistencyTokenFrom(ctx context.Context) string {
+	token, _ := ctx.Value(consistencyTokenKey{}).(string)
+	return token
+}
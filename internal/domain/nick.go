@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NickChange records a nick an account used to have and when it gave it up.
+type NickChange struct {
+	Nick       string    `json:"nick"`
+	ReleasedAt time.Time `json:"released_at"`
+}
+
+type NickRuleKind string
+
+const (
+	// NickRuleExact matches one nick.
+	NickRuleExact NickRuleKind = "exact"
+	// NickRulePrefix matches every nick starting with the value.
+	NickRulePrefix NickRuleKind = "prefix"
+	// NickRulePattern matches nicks against a regular expression.
+	NickRulePattern NickRuleKind = "pattern"
+)
+
+// NickRule reserves or blocks the nicks it matches. Values are compared with
+// the nick without its leading "@", ignoring case.
+type NickRule struct {
+	ID        uuid.UUID    `json:"id"`
+	Kind      NickRuleKind `json:"kind"`
+	Value     string       `json:"value"`
+	Reason    string       `json:"reason,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// NickNotAllowedError is returned for nicks rejected by the content filter.
+// It matches ErrNickNotAllowed with errors.Is.
+type NickNotAllowedError struct {
+	// Category names the wordlist that matched, e.g. "profanity" or
+	// "impersonation".
+	Category string
+}
+
+func (e *NickNotAllowedError) Error() string {
+	return ErrNickNotAllowed.Error() + ": " + e.Category
+}
+
+func (e *NickNotAllowedError) Is(target error) bool {
+	return target == ErrNickNotAllowed
+}
+
+// NickCheckReason is a machine-readable reason why a nick cannot be claimed.
+type NickCheckReason string
+
+const (
+	// NickCheckInvalidFormat means the nick breaks the syntax rules; no other
+	// checks are made.
+	NickCheckInvalidFormat NickCheckReason = "invalid_format"
+	// NickCheckReserved means a reserved nick rule matches.
+	NickCheckReserved NickCheckReason = "reserved"
+	// NickCheckNotAllowed means the content filter rejects the nick.
+	NickCheckNotAllowed NickCheckReason = "not_allowed"
+	// NickCheckTaken means another account holds the nick.
+	NickCheckTaken NickCheckReason = "taken"
+	// NickCheckCooldown means another account released the nick recently.
+	NickCheckCooldown NickCheckReason = "cooldown"
+)
+
+// NickCheck is the outcome of checking whether a nick can be claimed.
+type NickCheck struct {
+	Nick string
+	// Valid reports whether the nick passes the syntax rules, reserved nick
+	// rules and content filter.
+	Valid bool
+	// Available reports whether the nick is valid and can be claimed now.
+	Available bool
+	Reasons   []NickCheckReason
+	// Suggestions are available nicks derived from Nick, offered when it is
+	// not available.
+	Suggestions []string
+}
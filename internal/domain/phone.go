@@ -0,0 +1,46 @@
+package domain
+
+// PhoneType is the kind of service a phone number belongs to, as far as the
+// numbering plan tells.
+type PhoneType string
+
+const (
+	PhoneTypeUnknown     PhoneType = "unknown"
+	PhoneTypeMobile      PhoneType = "mobile"
+	PhoneTypeFixedLine   PhoneType = "fixed_line"
+	PhoneTypeVoIP        PhoneType = "voip"
+	PhoneTypePremiumRate PhoneType = "premium_rate"
+	PhoneTypeTollFree    PhoneType = "toll_free"
+)
+
+func (t PhoneType) Valid() bool {
+	switch t {
+	case PhoneTypeUnknown, PhoneTypeMobile, PhoneTypeFixedLine, PhoneTypeVoIP, PhoneTypePremiumRate, PhoneTypeTollFree:
+		return true
+	default:
+		return false
+	}
+}
+
+// Phone is a normalized phone number with its classification, as stored on
+// a new account.
+type Phone struct {
+	// Number is in E.164 format.
+	Number  string
+	Type    PhoneType
+	Flagged bool
+}
+
+// PhoneTypeNotAllowedError is returned for numbers whose type the signup
+// policy rejects. It matches ErrPhoneTypeNotAllowed with errors.Is.
+type PhoneTypeNotAllowedError struct {
+	Type PhoneType
+}
+
+func (e *PhoneTypeNotAllowedError) Error() string {
+	return ErrPhoneTypeNotAllowed.Error() + ": " + string(e.Type)
+}
+
+func (e *PhoneTypeNotAllowedError) Is(target error) bool {
+	return target == ErrPhoneTypeNotAllowed
+}
@@ -0,0 +1,27 @@
+package domain
+
+// Profile holds the optional presentation fields of an account, edited by its
+// owner. Empty fields are unset.
+type Profile struct {
+	DisplayName string `json:"display_name,omitempty"`
+	Bio         string `json:"bio,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	// AvatarThumbnailURL is set only for avatars uploaded to the service.
+	AvatarThumbnailURL string `json:"avatar_thumbnail_url,omitempty"`
+	// Locale is a canonical BCP 47 language tag, e.g. "en-US".
+	Locale string `json:"locale,omitempty"`
+	// TimeZone is an IANA time zone name, e.g. "Europe/Prague".
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+// ProfileUpdate is a partial profile change. Nil fields are left as they are;
+// a pointer to an empty string clears the field.
+type ProfileUpdate struct {
+	DisplayName *string
+	Bio         *string
+	AvatarURL   *string
+	// AvatarThumbnailURL is set by the service, never by clients.
+	AvatarThumbnailURL *string
+	Locale             *string
+	TimeZone           *string
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrRateLimited = errors.New("too many requests")
+
+// RateLimitedError is returned when a request exceeds a rate limit. It
+// matches ErrRateLimited with errors.Is.
+type RateLimitedError struct {
+	// Scope names the limit that was exceeded, e.g. "phone_prefix".
+	Scope string
+	// RetryAfter is how long the caller should wait before trying again.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return ErrRateLimited.Error() + ": " + e.Scope
+}
+
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
@@ -0,0 +1,183 @@
+// Package phone parses phone numbers written in common national and
+// international formats into canonical E.164.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// E.164 limits a number to 15 digits including the calling code.
+const maxE164Digits = 15
+
+var (
+	ErrInvalidFormat = errors.New("phone number contains invalid characters")
+	ErrUnknownRegion = errors.New("national phone number without a known default region")
+	ErrInvalidNumber = errors.New("phone number is not valid for its country")
+)
+
+// Number is a parsed phone number.
+type Number struct {
+	// CallingCode is the country calling code, e.g. "44". It is empty for
+	// calling codes Parse does not know, and National then holds all digits.
+	CallingCode string
+	// National is the national significant number, e.g. "2071838750".
+	National string
+	// Region is the ISO 3166-1 alpha-2 code of the country or territory,
+	// e.g. "GB". It is empty for calling codes Parse does not know.
+	Region string
+}
+
+// E164 returns the number as "+" followed by the calling code and national
+// number, e.g. "+442071838750".
+func (n Number) E164() string {
+	return "+" + n.CallingCode + n.National
+}
+
+var byRegion = func() map[string]region {
+	m := make(map[string]region, len(regions))
+	for _, r := range regions {
+		m[r.code] = r
+	}
+	return m
+}()
+
+// mainRegion maps each calling code to the first region using it.
+var mainRegion = func() map[string]region {
+	m := make(map[string]region, len(regions))
+	for _, r := range regions {
+		if _, ok := m[r.callingCode]; !ok {
+			m[r.callingCode] = r
+		}
+	}
+	return m
+}()
+
+// KnownRegion reports whether Parse knows the numbering plan of region, an
+// ISO 3166-1 alpha-2 code.
+func KnownRegion(code string) bool {
+	_, ok := byRegion[strings.ToUpper(code)]
+	return ok
+}
+
+// Parse reads raw in international form ("+44 20 7183 8750",
+// "0044 20 7183 8750") or, given defaultRegion, in national form
+// ("020 7183 8750"). Spaces, dots, dashes, slashes and parentheses are
+// ignored.
+func Parse(raw, defaultRegion string) (Number, error) {
+	digits, international, err := stripFormatting(raw)
+	if err != nil {
+		return Number{}, err
+	}
+
+	def, hasDefault := byRegion[strings.ToUpper(defaultRegion)]
+	if !international && hasDefault && def.intlPrefix != "" && strings.HasPrefix(digits, def.intlPrefix) {
+		digits, international = strings.TrimPrefix(digits, def.intlPrefix), true
+	}
+	if !international && strings.HasPrefix(digits, "00") {
+		digits, international = strings.TrimPrefix(digits, "00"), true
+	}
+
+	if international {
+		return parseInternational(digits)
+	}
+	if !hasDefault {
+		return Number{}, ErrUnknownRegion
+	}
+
+	return parseNational(digits, def)
+}
+
+// Normalize parses raw and returns its E.164 form.
+func Normalize(raw, defaultRegion string) (string, error) {
+	n, err := Parse(raw, defaultRegion)
+	if err != nil {
+		return "", err
+	}
+
+	return n.E164(), nil
+}
+
+func stripFormatting(raw string) (digits string, international bool, err error) {
+	raw = strings.TrimSpace(raw)
+	if rest, ok := strings.CutPrefix(raw, "+"); ok {
+		raw, international = rest, true
+	}
+
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '/' || r == '(' || r == ')' || r == '\u00a0':
+		default:
+			return "", false, ErrInvalidFormat
+		}
+	}
+	if b.Len() == 0 {
+		return "", false, ErrInvalidFormat
+	}
+
+	return b.String(), international, nil
+}
+
+func parseInternational(digits string) (Number, error) {
+	if digits[0] == '0' || len(digits) > maxE164Digits {
+		return Number{}, ErrInvalidNumber
+	}
+
+	for size := 1; size <= 3 && size < len(digits); size++ {
+		r, ok := mainRegion[digits[:size]]
+		if !ok {
+			continue
+		}
+
+		return check(Number{CallingCode: r.callingCode, National: digits[size:], Region: r.code}, r)
+	}
+
+	// An unknown calling code: only E.164's own bounds apply.
+	if len(digits) < 8 {
+		return Number{}, ErrInvalidNumber
+	}
+
+	return Number{National: digits}, nil
+}
+
+func parseNational(digits string, r region) (Number, error) {
+	n := Number{CallingCode: r.callingCode, National: digits, Region: r.code}
+	// A national number can itself start with the trunk digit, as Russian
+	// toll-free "800" numbers do, so only strip it if enough digits remain.
+	if rest, ok := strings.CutPrefix(digits, r.trunk); ok && r.trunk != "" && len(rest) >= r.minLen {
+		n.National = rest
+	}
+
+	return check(n, r)
+}
+
+// check validates the length of n against r and settles the region for
+// calling codes shared by several regions.
+func check(n Number, r region) (Number, error) {
+	if len(n.National) < r.minLen || len(n.National) > r.maxLen || len(n.CallingCode)+len(n.National) > maxE164Digits {
+		return Number{}, fmt.Errorf("%w: %s numbers have %d to %d digits", ErrInvalidNumber, r.code, r.minLen, r.maxLen)
+	}
+
+	switch n.CallingCode {
+	case "1":
+		// North American area codes never start with 0 or 1.
+		if n.National[0] < '2' {
+			return Number{}, ErrInvalidNumber
+		}
+		n.Region = "US"
+		if code, ok := nanpRegions[n.National[:3]]; ok {
+			n.Region = code
+		}
+	case "7":
+		n.Region = "RU"
+		if n.National[0] == '6' || n.National[0] == '7' {
+			n.Region = "KZ"
+		}
+	}
+
+	return n, nil
+}
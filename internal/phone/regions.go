@@ -0,0 +1,95 @@
+package phone
+
+// region describes the numbering plan of one country or territory.
+type region struct {
+	code        string
+	callingCode string
+	// trunk is the prefix dialled before national numbers inside the
+	// country, e.g. "0" in "030 1234567" for Berlin. It is not part of the
+	// international number.
+	trunk string
+	// minLen and maxLen bound the length of the national significant number.
+	minLen, maxLen int
+	// intlPrefix is dialled before a country calling code when calling
+	// abroad.
+	intlPrefix string
+}
+
+// regions lists the numbering plans Parse knows. Numbers with other calling
+// codes are accepted by the generic E.164 length rule. Regions sharing a
+// calling code come first for their main region, which is used when the
+// number does not say otherwise.
+var regions = []region{
+	{"US", "1", "1", 10, 10, "011"},
+	{"CA", "1", "1", 10, 10, "011"},
+	{"RU", "7", "8", 10, 10, "810"},
+	{"KZ", "7", "8", 10, 10, "810"},
+	{"EG", "20", "0", 8, 10, "00"},
+	{"ZA", "27", "0", 9, 9, "00"},
+	{"GR", "30", "", 10, 10, "00"},
+	{"NL", "31", "0", 9, 9, "00"},
+	{"BE", "32", "0", 8, 9, "00"},
+	{"FR", "33", "0", 9, 9, "00"},
+	{"ES", "34", "", 9, 9, "00"},
+	{"HU", "36", "06", 8, 9, "00"},
+	// Italian numbers keep their leading zero internationally.
+	{"IT", "39", "", 6, 11, "00"},
+	{"RO", "40", "0", 9, 9, "00"},
+	{"CH", "41", "0", 9, 9, "00"},
+	{"AT", "43", "0", 4, 13, "00"},
+	{"GB", "44", "0", 9, 10, "00"},
+	{"DK", "45", "", 8, 8, "00"},
+	{"SE", "46", "0", 7, 10, "00"},
+	{"NO", "47", "", 8, 8, "00"},
+	{"PL", "48", "", 9, 9, "00"},
+	{"DE", "49", "0", 6, 13, "00"},
+	{"PE", "51", "0", 8, 9, "00"},
+	{"MX", "52", "", 10, 10, "00"},
+	{"AR", "54", "0", 10, 10, "00"},
+	{"BR", "55", "0", 10, 11, "00"},
+	{"CL", "56", "", 9, 9, "00"},
+	{"CO", "57", "0", 10, 10, "00"},
+	{"AU", "61", "0", 9, 9, "0011"},
+	{"ID", "62", "0", 8, 12, "001"},
+	{"PH", "63", "0", 10, 10, "00"},
+	{"NZ", "64", "0", 8, 10, "00"},
+	{"SG", "65", "", 8, 8, "000"},
+	{"TH", "66", "0", 8, 9, "001"},
+	{"JP", "81", "0", 9, 10, "010"},
+	{"KR", "82", "0", 8, 10, "001"},
+	{"VN", "84", "0", 9, 10, "00"},
+	{"CN", "86", "0", 10, 11, "00"},
+	{"TR", "90", "0", 10, 10, "00"},
+	{"IN", "91", "0", 10, 10, "00"},
+	{"PK", "92", "0", 9, 10, "00"},
+	{"NG", "234", "0", 8, 10, "009"},
+	{"KE", "254", "0", 9, 9, "000"},
+	{"PT", "351", "", 9, 9, "00"},
+	{"IE", "353", "0", 7, 9, "00"},
+	{"FI", "358", "0", 5, 12, "00"},
+	{"UA", "380", "0", 9, 9, "00"},
+	{"CZ", "420", "", 9, 9, "00"},
+	{"SK", "421", "0", 9, 9, "00"},
+	{"IL", "972", "0", 8, 9, "00"},
+	{"AE", "971", "0", 8, 9, "00"},
+	{"SA", "966", "0", 9, 9, "00"},
+}
+
+// nanpRegions maps North American area codes outside the United States to
+// their region.
+var nanpRegions = map[string]string{
+	// Canada
+	"204": "CA", "226": "CA", "236": "CA", "249": "CA", "250": "CA", "263": "CA", "289": "CA",
+	"306": "CA", "343": "CA", "354": "CA", "365": "CA", "367": "CA", "368": "CA", "382": "CA",
+	"403": "CA", "416": "CA", "418": "CA", "428": "CA", "431": "CA", "437": "CA", "438": "CA",
+	"450": "CA", "468": "CA", "474": "CA", "506": "CA", "514": "CA", "519": "CA", "548": "CA",
+	"579": "CA", "581": "CA", "584": "CA", "587": "CA", "604": "CA", "613": "CA", "639": "CA",
+	"647": "CA", "672": "CA", "683": "CA", "705": "CA", "709": "CA", "742": "CA", "753": "CA",
+	"778": "CA", "780": "CA", "782": "CA", "807": "CA", "819": "CA", "825": "CA", "867": "CA",
+	"873": "CA", "879": "CA", "902": "CA", "905": "CA",
+	// Caribbean and Pacific
+	"242": "BS", "246": "BB", "264": "AI", "268": "AG", "284": "VG", "340": "VI", "345": "KY",
+	"441": "BM", "473": "GD", "649": "TC", "658": "JM", "876": "JM", "664": "MS", "670": "MP",
+	"671": "GU", "684": "AS", "721": "SX", "758": "LC", "767": "DM", "784": "VC", "787": "PR",
+	"939": "PR", "809": "DO", "829": "DO", "849": "DO", "868": "TT", "869": "KN",
+}
@@ -0,0 +1,230 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // register the GIF decoder with image.Decode
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register the WebP decoder with image.Decode
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+const (
+	defaultAvatarMaxBytes      = 5 << 20
+	defaultAvatarMaxDimension  = 4096
+	defaultAvatarThumbnailSize = 128
+	avatarJPEGQuality          = 90
+)
+
+// BlobStore stores uploaded files and serves them from stable URLs.
+type BlobStore interface {
+	// Put stores data under key, replacing any previous blob, and returns
+	// the URL it is served from.
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	Delete(ctx context.Context, key string) error
+	// Key returns the key of a URL returned by Put, or false if the store
+	// does not serve url.
+	Key(url string) (string, bool)
+}
+
+type AvatarConfig struct {
+	// MaxBytes limits the size of the uploaded file.
+	MaxBytes int64
+	// MaxDimension limits the width and height of the uploaded image, which
+	// bounds the memory needed to decode it.
+	MaxDimension int
+	// ThumbnailSize is the edge length of the square thumbnail.
+	ThumbnailSize int
+}
+
+// WithAvatarStore enables avatar uploads into store. Zero fields of cfg take
+// their defaults.
+func WithAvatarStore(store BlobStore, cfg AvatarConfig) Option {
+	return func(s *Service) {
+		if cfg.MaxBytes <= 0 {
+			cfg.MaxBytes = defaultAvatarMaxBytes
+		}
+		if cfg.MaxDimension <= 0 {
+			cfg.MaxDimension = defaultAvatarMaxDimension
+		}
+		if cfg.ThumbnailSize <= 0 {
+			cfg.ThumbnailSize = defaultAvatarThumbnailSize
+		}
+
+		s.avatars = store
+		s.avatarCfg = cfg
+	}
+}
+
+// avatarFormats lists the accepted upload formats by image.Decode name.
+var avatarFormats = map[string]bool{
+	"jpeg": true,
+	"png":  true,
+	"gif":  true,
+	"webp": true,
+}
+
+type encodedImage struct {
+	data        []byte
+	contentType string
+	ext         string
+}
+
+// UploadAvatar reads an image from r, stores it re-encoded together with a
+// square thumbnail, and points the account's avatar at them. Re-encoding
+// drops metadata such as EXIF location. Every upload gets new URLs, so they
+// can be cached indefinitely.
+func (s *Service) UploadAvatar(ctx context.Context, id uuid.UUID, r io.Reader) (domain.Account, error) {
+	if s.avatars == nil {
+		return domain.Account{}, domain.ErrAvatarUploadDisabled
+	}
+
+	// Fail before receiving the upload if it cannot be applied anyway.
+	acc, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Account{}, err
+	}
+	if acc.Status != domain.StatusActive {
+		return domain.Account{}, domain.ErrAccountNotActive
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.avatarCfg.MaxBytes+1))
+	if err != nil {
+		return domain.Account{}, fmt.Errorf("read avatar: %w", err)
+	}
+	if int64(len(data)) > s.avatarCfg.MaxBytes {
+		return domain.Account{}, domain.ErrAvatarTooLarge
+	}
+
+	full, thumbnail, err := processAvatar(data, s.avatarCfg)
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	prefix := fmt.Sprintf("avatars/%s/%s", id, uuid.New())
+	fullKey := prefix + full.ext
+	thumbnailKey := prefix + "_thumb" + thumbnail.ext
+
+	fullURL, err := s.avatars.Put(ctx, fullKey, full.contentType, full.data)
+	if err != nil {
+		return domain.Account{}, fmt.Errorf("store avatar: %w", err)
+	}
+	thumbnailURL, err := s.avatars.Put(ctx, thumbnailKey, thumbnail.contentType, thumbnail.data)
+	if err != nil {
+		s.deleteBlobs(ctx, fullKey)
+		return domain.Account{}, fmt.Errorf("store avatar thumbnail: %w", err)
+	}
+
+	// Clients render the URLs directly, so they must pass the same checks as
+	// avatar URLs set through UpdateProfile.
+	for _, u := range []*string{&fullURL, &thumbnailURL} {
+		normalized, ok := normalizeAvatarURL(*u)
+		if !ok {
+			s.deleteBlobs(ctx, fullKey, thumbnailKey)
+			return domain.Account{}, fmt.Errorf("store avatar: blob url %q is not a valid avatar url", *u)
+		}
+		*u = normalized
+	}
+
+	previous := acc.Profile
+	acc, err = s.repo.UpdateProfile(ctx, id, domain.ProfileUpdate{AvatarURL: &fullURL, AvatarThumbnailURL: &thumbnailURL})
+	if err != nil {
+		s.deleteBlobs(ctx, fullKey, thumbnailKey)
+		return domain.Account{}, err
+	}
+
+	s.deleteBlobs(ctx, s.avatarKeys(id, previous.AvatarURL, previous.AvatarThumbnailURL)...)
+
+	return acc, nil
+}
+
+// avatarKeys returns the keys of urls that are uploads of account id, so a
+// replaced avatar never deletes a URL set directly or another account's
+// upload.
+func (s *Service) avatarKeys(id uuid.UUID, urls ...string) []string {
+	prefix := fmt.Sprintf("avatars/%s/", id)
+
+	var keys []string
+	for _, u := range urls {
+		if key, ok := s.avatars.Key(u); ok && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// deleteBlobs removes blobs of a failed or replaced upload. Failures only
+// leave unreferenced files behind, so they are ignored.
+func (s *Service) deleteBlobs(ctx context.Context, keys ...string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		_ = s.avatars.Delete(ctx, key)
+	}
+}
+
+// processAvatar validates data and returns the re-encoded image and its
+// thumbnail. JPEG uploads stay JPEG; everything else becomes PNG, keeping
+// transparency. Animated GIFs keep only their first frame.
+func processAvatar(data []byte, cfg AvatarConfig) (encodedImage, encodedImage, error) {
+	header, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || !avatarFormats[format] {
+		return encodedImage{}, encodedImage{}, domain.ErrInvalidAvatar
+	}
+	if header.Width <= 0 || header.Height <= 0 || header.Width > cfg.MaxDimension || header.Height > cfg.MaxDimension {
+		return encodedImage{}, encodedImage{}, domain.ErrInvalidAvatar
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return encodedImage{}, encodedImage{}, domain.ErrInvalidAvatar
+	}
+
+	full, err := encodeAvatar(img, format)
+	if err != nil {
+		return encodedImage{}, encodedImage{}, err
+	}
+	thumbnail, err := encodeAvatar(squareThumbnail(img, cfg.ThumbnailSize), format)
+	if err != nil {
+		return encodedImage{}, encodedImage{}, err
+	}
+
+	return full, thumbnail, nil
+}
+
+func encodeAvatar(img image.Image, format string) (encodedImage, error) {
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: avatarJPEGQuality}); err != nil {
+			return encodedImage{}, fmt.Errorf("encode avatar: %w", err)
+		}
+		return encodedImage{data: buf.Bytes(), contentType: "image/jpeg", ext: ".jpg"}, nil
+	}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return encodedImage{}, fmt.Errorf("encode avatar: %w", err)
+	}
+	return encodedImage{data: buf.Bytes(), contentType: "image/png", ext: ".png"}, nil
+}
+
+// squareThumbnail crops the largest centered square out of img and scales it
+// to size×size.
+func squareThumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	edge := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, edge, edge).Add(b.Min).Add(image.Pt((b.Dx()-edge)/2, (b.Dy()-edge)/2))
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+
+	return dst
+}
@@ -0,0 +1,204 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+const (
+	defaultNickSuggestions = 5
+	maxNickSuggestions     = 20
+	// nickCandidatesPerSuggestion sizes the candidate list checked in one
+	// query, so that a few taken candidates still leave enough suggestions.
+	nickCandidatesPerSuggestion = 3
+)
+
+// CheckNick reports whether nick is valid and whether account id could claim
+// it now; uuid.Nil stands for a new account. When it cannot, up to
+// maxSuggestions available nicks derived from it are suggested (a default
+// when zero). Availability can change before the nick is claimed.
+func (s *Service) CheckNick(ctx context.Context, id uuid.UUID, nick string, maxSuggestions int) (domain.NickCheck, error) {
+	nick = strings.TrimSpace(nick)
+	if maxSuggestions <= 0 {
+		maxSuggestions = defaultNickSuggestions
+	}
+	maxSuggestions = min(maxSuggestions, maxNickSuggestions)
+
+	check := domain.NickCheck{Nick: nick}
+	if isValidNick(nick) {
+		reasons, err := s.nickContentReasons(ctx, nick)
+		if err != nil {
+			return domain.NickCheck{}, err
+		}
+		check.Reasons = reasons
+		check.Valid = len(reasons) == 0
+	} else {
+		check.Reasons = []domain.NickCheckReason{domain.NickCheckInvalidFormat}
+	}
+
+	var candidates []string
+	for _, candidate := range nickSuggestionCandidates(nick, maxSuggestions*nickCandidatesPerSuggestion) {
+		if reasons, err := s.nickContentReasons(ctx, candidate); err != nil {
+			return domain.NickCheck{}, err
+		} else if len(reasons) == 0 {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	// One query covers the nick itself and all candidates; the candidates are
+	// only used if the nick turns out to be unavailable.
+	toCheck := candidates
+	if check.Valid {
+		toCheck = append([]string{nick}, candidates...)
+	}
+	unavailable := map[string]domain.NickCheckReason{}
+	if len(toCheck) > 0 {
+		var err error
+		if unavailable, err = s.repo.CheckNicks(ctx, id, toCheck, s.nicks.Cooldown); err != nil {
+			return domain.NickCheck{}, err
+		}
+	}
+
+	if reason, ok := unavailable[strings.ToLower(nick)]; ok {
+		check.Reasons = append(check.Reasons, reason)
+	}
+	check.Available = check.Valid && len(check.Reasons) == 0
+	if check.Available {
+		return check, nil
+	}
+
+	for _, candidate := range candidates {
+		if len(check.Suggestions) == maxSuggestions {
+			break
+		}
+		if _, ok := unavailable[strings.ToLower(candidate)]; !ok {
+			check.Suggestions = append(check.Suggestions, candidate)
+		}
+	}
+
+	return check, nil
+}
+
+// nickContentReasons applies the reserved nick rules and the content filter
+// to a syntactically valid nick and returns the reasons it fails them.
+func (s *Service) nickContentReasons(ctx context.Context, nick string) ([]domain.NickCheckReason, error) {
+	var reasons []domain.NickCheckReason
+	if s.checkNickRules(nick) != nil {
+		reasons = append(reasons, domain.NickCheckReserved)
+	}
+
+	err := s.checkNickFilter(ctx, nick)
+	switch {
+	case errors.Is(err, domain.ErrNickNotAllowed):
+		reasons = append(reasons, domain.NickCheckNotAllowed)
+	case err != nil:
+		return nil, err
+	}
+
+	return reasons, nil
+}
+
+// nickSuggestionCandidates derives up to n distinct valid nicks from nick:
+// separator variants first, then numeric suffixes of growing length. Numbers
+// are random so that popular names do not all get the same suggestions.
+func nickSuggestionCandidates(nick string, n int) []string {
+	base := sanitizeNickBase(nick)
+	if len(base) < 2 {
+		return nil
+	}
+
+	seen := map[string]bool{strings.ToLower("@" + base): true}
+	var out []string
+	add := func(name string) {
+		candidate := "@" + name
+		if len(out) < n && isValidNick(candidate) && !seen[strings.ToLower(candidate)] {
+			seen[strings.ToLower(candidate)] = true
+			out = append(out, candidate)
+		}
+	}
+	withSuffix := func(suffix string) {
+		add(truncateNickBase(base, MaxNickLength-len(suffix)) + suffix)
+	}
+
+	if strings.Contains(base, "_") {
+		add(strings.ReplaceAll(base, "_", ""))
+	} else if split := splitLettersDigits(base); split != base {
+		add(split)
+	}
+	withSuffix("_")
+
+	for attempts := 0; len(out) < n && attempts < 4*n; attempts++ {
+		digits := 2 + attempts/n
+		number, err := randomDigits(digits)
+		if err != nil {
+			break
+		}
+		if attempts%2 == 0 {
+			withSuffix(number)
+		} else {
+			withSuffix("_" + number)
+		}
+	}
+
+	return out
+}
+
+// sanitizeNickBase strips the leading "@" and replaces runs of characters a
+// nick cannot contain with "_".
+func sanitizeNickBase(nick string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimPrefix(nick, "@") {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case !strings.HasSuffix(b.String(), "_") && b.Len() > 0:
+			b.WriteByte('_')
+		}
+	}
+
+	return truncateNickBase(strings.TrimSuffix(b.String(), "_"), MaxNickLength)
+}
+
+func truncateNickBase(base string, max int) string {
+	if len(base) > max {
+		base = base[:max]
+	}
+
+	return base
+}
+
+// splitLettersDigits inserts "_" where letters meet digits, e.g. "alice99"
+// becomes "alice_99".
+func splitLettersDigits(base string) string {
+	var b strings.Builder
+	for i := range len(base) {
+		if i > 0 && isDigit(base[i]) != isDigit(base[i-1]) {
+			b.WriteByte('_')
+		}
+		b.WriteByte(base[i])
+	}
+
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func randomDigits(n int) (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	v, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", fmt.Errorf("generate nick: %w", err)
+	}
+
+	return fmt.Sprintf("%0*d", n, v), nil
+}
@@ -0,0 +1,287 @@
+package account
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+// NickFilter decides whether a nick's content is acceptable.
+type NickFilter interface {
+	// Check returns a *domain.NickNotAllowedError for unacceptable nicks.
+	// Other errors mean the nick could not be checked.
+	Check(ctx context.Context, nick string) error
+}
+
+// WithNickFilter rejects new nicks that filter does not accept.
+func WithNickFilter(filter NickFilter) Option {
+	return func(s *Service) {
+		s.nickFilter = filter
+	}
+}
+
+func (s *Service) checkNickFilter(ctx context.Context, nick string) error {
+	if s.nickFilter == nil {
+		return nil
+	}
+
+	return s.nickFilter.Check(ctx, nick)
+}
+
+// WordlistFilter rejects nicks containing a term from one of its wordlists.
+// Nicks and terms are compared by their skeleton, which undoes common
+// evasions: case, accents, look-alike letters from other scripts, leetspeak
+// digits and symbols, separators and repeated letters. "@Ph_4DM1N" and
+// "@аdmіn" (Cyrillic а and і) both have the skeleton of "admin".
+type WordlistFilter struct {
+	// terms is sorted by category for deterministic matches.
+	terms []wordlistTerm
+	allow []skeleton
+}
+
+type wordlistTerm struct {
+	category string
+	skeleton skeleton
+	// whole requires the term to be the entire nick rather than part of it,
+	// for short terms that occur inside many innocent words.
+	whole bool
+}
+
+var _ NickFilter = (*WordlistFilter)(nil)
+
+// NewWordlistFilter builds a filter from wordlists keyed by category, in the
+// format read by ParseWordlist. A term found inside an occurrence of an allow
+// term is ignored, so "scunthorpe" on the allowlist lets "@scunthorpe_fc"
+// through but not "@scunthorpe_cunt".
+func NewWordlistFilter(lists map[string][]string, allow []string) *WordlistFilter {
+	f := &WordlistFilter{}
+
+	categories := make([]string, 0, len(lists))
+	for category := range lists {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		for _, term := range lists[category] {
+			whole := strings.HasPrefix(term, "=")
+			if sk := newSkeleton(strings.TrimPrefix(term, "=")); sk.plain != "" {
+				f.terms = append(f.terms, wordlistTerm{category: category, skeleton: sk, whole: whole})
+			}
+		}
+	}
+	for _, term := range allow {
+		if sk := newSkeleton(term); sk.plain != "" {
+			f.allow = append(f.allow, sk)
+		}
+	}
+
+	return f
+}
+
+func (f *WordlistFilter) Check(_ context.Context, nick string) error {
+	sk, leet := nickSkeleton(nick)
+
+	// Nicks using leetspeak may also write "l" as "1", "|" or "!", which
+	// fold to "i", so they are compared with the folded terms as well.
+	forms := []bool{false}
+	if leet {
+		forms = append(forms, true)
+	}
+
+	for _, term := range f.terms {
+		for _, folded := range forms {
+			t := term.skeleton.get(folded)
+			if term.whole {
+				if sk == t {
+					return &domain.NickNotAllowedError{Category: term.category}
+				}
+				continue
+			}
+			for i := strings.Index(sk, t); i >= 0; i = nextIndex(sk, t, i) {
+				if !f.allowed(sk, i, i+len(t), forms) {
+					return &domain.NickNotAllowedError{Category: term.category}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// allowed reports whether sk[start:end] lies inside an occurrence of an allow
+// term.
+func (f *WordlistFilter) allowed(sk string, start, end int, forms []bool) bool {
+	for _, allow := range f.allow {
+		for _, folded := range forms {
+			a := allow.get(folded)
+			for i := max(end-len(a), 0); i <= start; i++ {
+				if strings.HasPrefix(sk[i:], a) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// nextIndex returns the index of the next occurrence of sub in s after the
+// one at i, or -1.
+func nextIndex(s, sub string, i int) int {
+	j := strings.Index(s[i+1:], sub)
+	if j < 0 {
+		return -1
+	}
+
+	return i + 1 + j
+}
+
+// skeleton holds both skeletons of a wordlist term, to compare it with nicks
+// with and without leetspeak.
+type skeleton struct {
+	plain  string
+	folded string
+}
+
+func newSkeleton(term string) skeleton {
+	plain, _ := nickSkeleton(term)
+
+	return skeleton{plain: plain, folded: collapse(strings.ReplaceAll(plain, "l", "i"))}
+}
+
+func (s skeleton) get(folded bool) string {
+	if folded {
+		return s.folded
+	}
+
+	return s.plain
+}
+
+// homoglyphs maps letters from other scripts that render like a Latin letter
+// to that letter. Accented Latin letters are handled by stripping marks.
+var homoglyphs = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'з': 'z', 'і': 'i', 'ї': 'i', 'ј': 'j', 'к': 'k',
+	'м': 'm', 'н': 'h', 'о': 'o', 'п': 'n', 'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x',
+	'ѕ': 's', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'ь': 'b',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p',
+	'τ': 't', 'υ': 'u', 'χ': 'x', 'ω': 'w', 'ζ': 'z',
+	// Latin look-alikes that do not decompose
+	'ı': 'i', 'ł': 'l', 'ø': 'o', 'đ': 'd', 'ħ': 'h', 'ß': 's',
+}
+
+// leetspeak maps digits and symbols used in place of letters. "1", "|" and
+// "!" stand in for both "i" and "l", so an "l" next to a substituted
+// character is folded into "i" as well.
+var leetspeak = map[rune]rune{
+	'0': 'o', '1': 'i', '2': 'z', '3': 'e', '4': 'a', '5': 's', '6': 'g', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i', '+': 't',
+}
+
+// nickLetter is a letter of a nick skeleton and how it was written.
+type nickLetter struct {
+	r     rune
+	digit bool
+	leet  bool
+}
+
+// nickSkeleton reduces s to lowercase Latin letters after folding homoglyphs
+// and leetspeak, dropping everything else, and collapses repeated letters. An
+// "l" next to a substituted character is folded into "i". Digits at the end
+// of s are read as letters but not as leetspeak, so "@shilton1" is not
+// treated as evasive. It also reports whether s used leetspeak.
+func nickSkeleton(s string) (string, bool) {
+	s = strings.TrimPrefix(s, "@")
+
+	var letters []nickLetter
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if m, ok := homoglyphs[r]; ok {
+			r = m
+		}
+		l := nickLetter{digit: r >= '0' && r <= '9'}
+		if m, ok := leetspeak[r]; ok {
+			r = m
+			l.leet = true
+		}
+		if r < 'a' || r > 'z' {
+			continue
+		}
+		l.r = r
+		if n := len(letters); n > 0 && letters[n-1].r == r {
+			letters[n-1].digit = letters[n-1].digit && l.digit
+			letters[n-1].leet = letters[n-1].leet || l.leet
+			continue
+		}
+		letters = append(letters, l)
+	}
+	for i := len(letters) - 1; i >= 0 && letters[i].digit; i-- {
+		letters[i].leet = false
+	}
+
+	var b strings.Builder
+	leet := false
+	for i, l := range letters {
+		if l.r == 'l' && (i > 0 && letters[i-1].leet || i+1 < len(letters) && letters[i+1].leet) {
+			l.r = 'i'
+		}
+		b.WriteRune(l.r)
+		leet = leet || l.leet
+	}
+
+	return collapse(b.String()), leet
+}
+
+// collapse drops repeated letters from s.
+func collapse(s string) string {
+	var b strings.Builder
+	var last rune
+	for _, r := range s {
+		if r != last {
+			b.WriteRune(r)
+		}
+		last = r
+	}
+
+	return b.String()
+}
+
+// ParseWordlist reads one term per line. A leading "=" makes the term match
+// whole nicks only. Blank lines and lines starting with "#" are ignored.
+func ParseWordlist(r io.Reader) ([]string, error) {
+	var terms []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		term := strings.TrimSpace(scanner.Text())
+		if term == "" || strings.HasPrefix(term, "#") {
+			continue
+		}
+		terms = append(terms, term)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read wordlist: %w", err)
+	}
+
+	return terms, nil
+}
+
+// isNickRejected reports whether err rejects the nick itself, as opposed to
+// failing to check it.
+func isNickRejected(err error) bool {
+	return errors.Is(err, domain.ErrNickReserved) || errors.Is(err, domain.ErrNickNotAllowed)
+}
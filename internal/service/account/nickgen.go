@@ -0,0 +1,267 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	defaultNickGenerationAttempts = 10
+	defaultRandomNickLength       = 10
+	// maxNickWordLength and maxNickWordDigits keep "@<adjective>_<noun><digits>"
+	// within the 30 characters a nick may have after "@".
+	maxNickWordLength = 10
+	maxNickWordDigits = 8
+)
+
+// NickGenerator proposes nicks for new accounts. The service checks each
+// proposal against the nick rules, the content filter and existing accounts,
+// and asks again until one can be claimed.
+type NickGenerator interface {
+	// Generate returns a nick for an account whose earlier proposals
+	// collided with existing nicks collisions times, letting the generator
+	// widen its space. locale is a BCP 47 tag or empty.
+	Generate(ctx context.Context, locale string, collisions int) (string, error)
+}
+
+// WithNickGenerator replaces the default RandomNickGenerator. Create gives up
+// with domain.ErrNickAlreadyExists after maxAttempts proposals (10 when
+// zero).
+func WithNickGenerator(gen NickGenerator, maxAttempts int) Option {
+	return func(s *Service) {
+		if maxAttempts <= 0 {
+			maxAttempts = defaultNickGenerationAttempts
+		}
+
+		s.nickGen = gen
+		s.nickGenAttempts = maxAttempts
+	}
+}
+
+// RandomNickGenerator proposes nicks of random lowercase letters and digits,
+// e.g. "@x7k2m9qa4z", one character longer per collision.
+type RandomNickGenerator struct {
+	length int
+}
+
+var _ NickGenerator = (*RandomNickGenerator)(nil)
+
+// NewRandomNickGenerator proposes nicks with length characters after "@",
+// 10 when zero.
+func NewRandomNickGenerator(length int) *RandomNickGenerator {
+	if length <= 0 {
+		length = defaultRandomNickLength
+	}
+
+	return &RandomNickGenerator{length: min(length, MaxNickLength)}
+}
+
+func (g *RandomNickGenerator) Generate(_ context.Context, _ string, collisions int) (string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	buf := make([]byte, min(g.length+collisions, MaxNickLength))
+	for i := range buf {
+		n, err := randomIndex(len(alphabet))
+		if err != nil {
+			return "", err
+		}
+		buf[i] = alphabet[n]
+	}
+
+	return "@" + string(buf), nil
+}
+
+// NickWords are the words a WordlistNickGenerator combines for one locale.
+type NickWords struct {
+	Adjectives []string
+	Nouns      []string
+}
+
+// WordlistNickGenerator proposes "@<adjective>_<noun><digits>" nicks such as
+// "@brave_otter4821" from per-locale wordlists. Each collision adds a digit.
+type WordlistNickGenerator struct {
+	// words[0] belongs to the default locale.
+	words   []NickWords
+	matcher language.Matcher
+	digits  int
+}
+
+var _ NickGenerator = (*WordlistNickGenerator)(nil)
+
+// NewWordlistNickGenerator builds a generator from words keyed by BCP 47
+// locale. Requests for other locales get the closest match, or
+// defaultLocale. Words are folded to lowercase ASCII letters ("Müde" becomes
+// "mude"); words that end up empty or longer than 10 letters are skipped.
+func NewWordlistNickGenerator(words map[string]NickWords, defaultLocale string, digits int) (*WordlistNickGenerator, error) {
+	if _, ok := words[defaultLocale]; !ok {
+		return nil, fmt.Errorf("no nick words for default locale %q", defaultLocale)
+	}
+	if digits < 0 || digits > maxNickWordDigits {
+		return nil, fmt.Errorf("nick digits must be between 0 and %d, got %d", maxNickWordDigits, digits)
+	}
+
+	g := &WordlistNickGenerator{digits: digits}
+	locales := append([]string{defaultLocale}, mapKeysExcept(words, defaultLocale)...)
+	tags := make([]language.Tag, 0, len(locales))
+	for _, locale := range locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf("nick words locale %q: %w", locale, err)
+		}
+
+		w := NickWords{Adjectives: foldNickWords(words[locale].Adjectives), Nouns: foldNickWords(words[locale].Nouns)}
+		if len(w.Adjectives) == 0 || len(w.Nouns) == 0 {
+			return nil, fmt.Errorf("nick words for locale %q need at least one adjective and one noun", locale)
+		}
+
+		tags = append(tags, tag)
+		g.words = append(g.words, w)
+	}
+	g.matcher = language.NewMatcher(tags)
+
+	return g, nil
+}
+
+func (g *WordlistNickGenerator) Generate(_ context.Context, locale string, collisions int) (string, error) {
+	w := g.words[0]
+	if tag, err := language.Parse(locale); locale != "" && err == nil {
+		if _, i, confidence := g.matcher.Match(tag); confidence != language.No {
+			w = g.words[i]
+		}
+	}
+
+	adjective, err := randomWord(w.Adjectives)
+	if err != nil {
+		return "", err
+	}
+	noun, err := randomWord(w.Nouns)
+	if err != nil {
+		return "", err
+	}
+
+	nick := "@" + adjective + "_" + noun
+	if digits := min(g.digits+collisions, maxNickWordDigits); digits > 0 {
+		number, err := randomDigits(digits)
+		if err != nil {
+			return "", err
+		}
+		nick += number
+	}
+
+	return nick, nil
+}
+
+// LoadNickWords reads <locale>/adjectives.txt and <locale>/nouns.txt, in the
+// format read by ParseWordlist, for every locale directory in fsys.
+func LoadNickWords(fsys fs.FS) (map[string]NickWords, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("list nick words: %w", err)
+	}
+
+	words := make(map[string]NickWords)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		locale := entry.Name()
+		var w NickWords
+		if w.Adjectives, err = readNickWords(fsys, locale+"/adjectives.txt"); err != nil {
+			return nil, err
+		}
+		if w.Nouns, err = readNickWords(fsys, locale+"/nouns.txt"); err != nil {
+			return nil, err
+		}
+		words[locale] = w
+	}
+	if len(words) == 0 {
+		return nil, errors.New("no nick word locales found")
+	}
+
+	return words, nil
+}
+
+func readNickWords(fsys fs.FS, name string) ([]string, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("read nick words: %w", err)
+	}
+
+	terms, err := ParseWordlist(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse nick words %s: %w", name, err)
+	}
+
+	return terms, nil
+}
+
+// foldNickWords strips accents and drops words that still contain anything
+// but ASCII letters, as well as duplicates.
+func foldNickWords(words []string) []string {
+	seen := make(map[string]bool, len(words))
+	out := make([]string, 0, len(words))
+	for _, word := range words {
+		var b strings.Builder
+		valid := true
+		for _, r := range norm.NFKD.String(strings.ToLower(word)) {
+			switch {
+			case unicode.Is(unicode.Mn, r):
+			case r >= 'a' && r <= 'z':
+				b.WriteRune(r)
+			default:
+				valid = false
+			}
+		}
+
+		folded := b.String()
+		if valid && folded != "" && len(folded) <= maxNickWordLength && !seen[folded] {
+			seen[folded] = true
+			out = append(out, folded)
+		}
+	}
+
+	return out
+}
+
+func mapKeysExcept(words map[string]NickWords, except string) []string {
+	keys := make([]string, 0, len(words))
+	for k := range words {
+		if k != except {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func randomWord(words []string) (string, error) {
+	i, err := randomIndex(len(words))
+	if err != nil {
+		return "", err
+	}
+
+	return words[i], nil
+}
+
+// randomIndex returns a uniformly distributed index below n.
+func randomIndex(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("generate nick: %w", err)
+	}
+
+	return int(v.Int64()), nil
+}
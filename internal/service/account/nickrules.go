@@ -0,0 +1,278 @@
+package account
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+const maxNickRuleValueLength = 255
+
+var nickRuleValuePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// NickRuleStore persists the reserved and blocked nick rules shared by all
+// service replicas.
+type NickRuleStore interface {
+	ListNickRules(ctx context.Context) ([]domain.NickRule, error)
+	// AddNickRule returns domain.ErrNickRuleExists if a rule of the same
+	// kind and value exists.
+	AddNickRule(ctx context.Context, rule domain.NickRule) (domain.NickRule, error)
+	DeleteNickRule(ctx context.Context, id uuid.UUID) error
+}
+
+// WithNickRules checks new nicks against the rules in store. Rules are loaded
+// by RefreshNickRules and SyncNickRules.
+func WithNickRules(store NickRuleStore) Option {
+	return func(s *Service) {
+		s.nickRuleStore = store
+	}
+}
+
+// nickRuleSet is an immutable, compiled snapshot of the nick rules.
+type nickRuleSet struct {
+	exact    map[string]domain.NickRule
+	prefixes []domain.NickRule
+	patterns []compiledNickRule
+}
+
+type compiledNickRule struct {
+	rule domain.NickRule
+	re   *regexp.Regexp
+}
+
+func compileNickRules(rules []domain.NickRule) *nickRuleSet {
+	set := &nickRuleSet{exact: make(map[string]domain.NickRule)}
+	for _, rule := range rules {
+		switch rule.Kind {
+		case domain.NickRuleExact:
+			set.exact[rule.Value] = rule
+		case domain.NickRulePrefix:
+			set.prefixes = append(set.prefixes, rule)
+		case domain.NickRulePattern:
+			// Stored patterns were validated when added; skip any that no
+			// longer compile rather than rejecting every nick.
+			if re, err := compileNickPattern(rule.Value); err == nil {
+				set.patterns = append(set.patterns, compiledNickRule{rule: rule, re: re})
+			}
+		}
+	}
+
+	return set
+}
+
+// match returns the first rule matching nick.
+func (set *nickRuleSet) match(nick string) (domain.NickRule, bool) {
+	if set == nil {
+		return domain.NickRule{}, false
+	}
+
+	name := strings.ToLower(strings.TrimPrefix(nick, "@"))
+	if rule, ok := set.exact[name]; ok {
+		return rule, true
+	}
+	for _, rule := range set.prefixes {
+		if strings.HasPrefix(name, rule.Value) {
+			return rule, true
+		}
+	}
+	for _, p := range set.patterns {
+		if p.re.MatchString(name) {
+			return p.rule, true
+		}
+	}
+
+	return domain.NickRule{}, false
+}
+
+func (s *Service) checkNickRules(nick string) error {
+	if _, ok := s.nickRules.Load().match(nick); ok {
+		return domain.ErrNickReserved
+	}
+
+	return nil
+}
+
+// RefreshNickRules reloads the rules from the store.
+func (s *Service) RefreshNickRules(ctx context.Context) error {
+	if s.nickRuleStore == nil {
+		return nil
+	}
+
+	rules, err := s.nickRuleStore.ListNickRules(ctx)
+	if err != nil {
+		return fmt.Errorf("load nick rules: %w", err)
+	}
+	s.nickRules.Store(compileNickRules(rules))
+
+	return nil
+}
+
+// SyncNickRules refreshes the rules every interval until ctx is done, picking
+// up changes made through other replicas. Failed refreshes keep the previous
+// rules.
+func (s *Service) SyncNickRules(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RefreshNickRules(ctx); err != nil && ctx.Err() == nil {
+				s.metrics.IncNickRuleRefreshFailure(telemetry.NickRuleRefreshSync)
+				if onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}
+}
+
+// SeedNickRules adds rules that do not exist yet and reloads the rule set.
+func (s *Service) SeedNickRules(ctx context.Context, rules []domain.NickRule) error {
+	if s.nickRuleStore == nil {
+		return domain.ErrNickRulesDisabled
+	}
+
+	for _, seed := range rules {
+		rule, err := normalizeNickRule(seed)
+		if err != nil {
+			return fmt.Errorf("seed nick rule %s %q: %w", seed.Kind, seed.Value, err)
+		}
+		if _, err = s.nickRuleStore.AddNickRule(ctx, rule); err != nil && !errors.Is(err, domain.ErrNickRuleExists) {
+			return fmt.Errorf("seed nick rule %s %q: %w", seed.Kind, seed.Value, err)
+		}
+	}
+
+	return s.RefreshNickRules(ctx)
+}
+
+func (s *Service) ListNickRules(ctx context.Context) ([]domain.NickRule, error) {
+	if s.nickRuleStore == nil {
+		return nil, domain.ErrNickRulesDisabled
+	}
+
+	return s.nickRuleStore.ListNickRules(ctx)
+}
+
+// AddNickRule stores rule and applies it on this replica immediately. Other
+// replicas apply it on their next sync. Accounts already holding a matching
+// nick keep it. Once the rule is stored, a failure to reload the rules is only
+// counted; the next sync applies it.
+func (s *Service) AddNickRule(ctx context.Context, rule domain.NickRule) (domain.NickRule, error) {
+	if s.nickRuleStore == nil {
+		return domain.NickRule{}, domain.ErrNickRulesDisabled
+	}
+
+	rule, err := normalizeNickRule(rule)
+	if err != nil {
+		return domain.NickRule{}, err
+	}
+
+	rule, err = s.nickRuleStore.AddNickRule(ctx, rule)
+	if err != nil {
+		return domain.NickRule{}, err
+	}
+	s.refreshAfterWrite(ctx)
+
+	return rule, nil
+}
+
+// RemoveNickRule deletes a rule; like AddNickRule, it only counts a failure
+// to reload the rules afterwards.
+func (s *Service) RemoveNickRule(ctx context.Context, id uuid.UUID) error {
+	if s.nickRuleStore == nil {
+		return domain.ErrNickRulesDisabled
+	}
+
+	if err := s.nickRuleStore.DeleteNickRule(ctx, id); err != nil {
+		return err
+	}
+	s.refreshAfterWrite(ctx)
+
+	return nil
+}
+
+// refreshAfterWrite applies a committed rule change on this replica. A failed
+// reload is left to the periodic sync.
+func (s *Service) refreshAfterWrite(ctx context.Context) {
+	if err := s.RefreshNickRules(ctx); err != nil {
+		s.metrics.IncNickRuleRefreshFailure(telemetry.NickRuleRefreshWrite)
+	}
+}
+
+// normalizeNickRule validates rule and assigns it an id. Exact and prefix
+// values are stored lowercase without the leading "@".
+func normalizeNickRule(rule domain.NickRule) (domain.NickRule, error) {
+	rule.Value = strings.TrimSpace(rule.Value)
+	rule.Reason = strings.TrimSpace(rule.Reason)
+	if rule.Value == "" || len(rule.Value) > maxNickRuleValueLength {
+		return domain.NickRule{}, domain.ErrInvalidNickRule
+	}
+
+	switch rule.Kind {
+	case domain.NickRuleExact, domain.NickRulePrefix:
+		rule.Value = strings.ToLower(strings.TrimPrefix(rule.Value, "@"))
+		if !nickRuleValuePattern.MatchString(rule.Value) {
+			return domain.NickRule{}, domain.ErrInvalidNickRule
+		}
+	case domain.NickRulePattern:
+		if _, err := compileNickPattern(rule.Value); err != nil {
+			return domain.NickRule{}, domain.ErrInvalidNickRule
+		}
+	default:
+		return domain.NickRule{}, domain.ErrInvalidNickRule
+	}
+
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+	}
+
+	return rule, nil
+}
+
+func compileNickPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// ParseNickRules reads rules in the seed file format: one "<kind> <value>
+// [reason]" per line, where kind is exact, prefix or pattern. Blank lines and
+// lines starting with "#" are ignored.
+func ParseNickRules(r io.Reader) ([]domain.NickRule, error) {
+	var rules []domain.NickRule
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: expected \"<kind> <value> [reason]\"", line)
+		}
+
+		rules = append(rules, domain.NickRule{
+			Kind:   domain.NickRuleKind(fields[0]),
+			Value:  fields[1],
+			Reason: strings.Join(fields[2:], " "),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read nick rules: %w", err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,53 @@
+package account
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+const (
+	defaultNickRedirectPeriod = 30 * 24 * time.Hour
+	defaultNickCooldown       = 14 * 24 * time.Hour
+)
+
+type NickHistoryConfig struct {
+	// RedirectPeriod is how long a released nick keeps resolving to the
+	// account that released it, unless another account has claimed it.
+	RedirectPeriod time.Duration
+	// Cooldown is how long other accounts cannot claim a released nick. The
+	// account that released it can take it back at any time.
+	Cooldown time.Duration
+}
+
+// WithNickHistory overrides the default 30-day redirect period and 14-day
+// cooldown for released nicks. Zero disables either.
+func WithNickHistory(cfg NickHistoryConfig) Option {
+	return func(s *Service) {
+		s.nicks = cfg
+	}
+}
+
+// GetByNick resolves nick to the account holding it or, within the redirect
+// period, to the account that most recently released it.
+func (s *Service) GetByNick(ctx context.Context, nick string) (domain.Account, error) {
+	nick = strings.TrimSpace(nick)
+	if !isValidNick(nick) {
+		return domain.Account{}, domain.ErrInvalidNick
+	}
+
+	return s.repo.GetByNick(ctx, nick, s.nicks.RedirectPeriod)
+}
+
+// ListNickHistory returns the nicks the account has released, newest first.
+func (s *Service) ListNickHistory(ctx context.Context, id uuid.UUID) ([]domain.NickChange, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListNickHistory(ctx, id)
+}
@@ -0,0 +1,26 @@
+package account
+
+import (
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/phone"
+)
+
+// WithPhoneRegion accepts phone numbers in the national format of region, an
+// ISO 3166-1 alpha-2 code such as "DE". Without it only international numbers
+// are accepted.
+func WithPhoneRegion(region string) Option {
+	return func(s *Service) {
+		s.phoneRegion = region
+	}
+}
+
+// normalizePhone returns the canonical E.164 form of raw, so that different
+// formattings of one number cannot become different accounts.
+func (s *Service) normalizePhone(raw string) (string, error) {
+	e164, err := phone.Normalize(raw, s.phoneRegion)
+	if err != nil {
+		return "", domain.ErrInvalidPhone
+	}
+
+	return e164, nil
+}
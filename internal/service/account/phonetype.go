@@ -0,0 +1,174 @@
+package account
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+// PhoneClassifier tells what kind of service a phone number belongs to.
+type PhoneClassifier interface {
+	// Classify returns the type of e164, or domain.PhoneTypeUnknown if it
+	// cannot tell.
+	Classify(ctx context.Context, e164 string) (domain.PhoneType, error)
+}
+
+// PhoneTypePolicy decides what happens to new accounts by the type of their
+// phone number. Types in neither list are accepted.
+type PhoneTypePolicy struct {
+	// Reject fails Create with a *domain.PhoneTypeNotAllowedError.
+	Reject []domain.PhoneType
+	// Flag creates the account with Account.PhoneFlagged set.
+	Flag []domain.PhoneType
+}
+
+// WithPhoneClassifier classifies the phone number of new accounts, stores
+// the type on the account and applies policy. Without it every number is
+// stored as domain.PhoneTypeUnknown.
+func WithPhoneClassifier(c PhoneClassifier, policy PhoneTypePolicy) Option {
+	return func(s *Service) {
+		s.phoneClassifier = c
+		s.phonePolicy = policy
+	}
+}
+
+// classifyPhone applies the phone type policy to the normalized number e164.
+func (s *Service) classifyPhone(ctx context.Context, e164 string) (domain.Phone, error) {
+	p := domain.Phone{Number: e164, Type: domain.PhoneTypeUnknown}
+	if s.phoneClassifier == nil {
+		return p, nil
+	}
+
+	var err error
+	if p.Type, err = s.phoneClassifier.Classify(ctx, e164); err != nil {
+		return domain.Phone{}, fmt.Errorf("classify phone: %w", err)
+	}
+
+	switch {
+	case slices.Contains(s.phonePolicy.Reject, p.Type):
+		s.metrics.IncPhoneClassification(string(p.Type), telemetry.PhoneClassificationRejected)
+		return domain.Phone{}, &domain.PhoneTypeNotAllowedError{Type: p.Type}
+	case slices.Contains(s.phonePolicy.Flag, p.Type):
+		s.metrics.IncPhoneClassification(string(p.Type), telemetry.PhoneClassificationFlagged)
+		p.Flagged = true
+	default:
+		s.metrics.IncPhoneClassification(string(p.Type), telemetry.PhoneClassificationAccepted)
+	}
+
+	return p, nil
+}
+
+// NumberingPlan classifies phone numbers by the longest matching prefix in
+// an offline numbering-plan dataset. Load replaces the dataset while the
+// plan is in use; the zero value classifies every number as unknown.
+type NumberingPlan struct {
+	prefixes atomic.Pointer[numberingPlanPrefixes]
+}
+
+type numberingPlanPrefixes struct {
+	// types is keyed by calling code and national number prefix, without
+	// the "+".
+	types   map[string]domain.PhoneType
+	longest int
+}
+
+var _ PhoneClassifier = (*NumberingPlan)(nil)
+
+// ParseNumberingPlan reads a plan in the format described at Load.
+func ParseNumberingPlan(r io.Reader) (*NumberingPlan, error) {
+	p := &NumberingPlan{}
+	if err := p.Load(r); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Load replaces the dataset with the one read from r, or keeps the current
+// one if r cannot be parsed. Each line holds an E.164 prefix and a type,
+// e.g. "+44800 toll_free"; blank lines and lines starting with "#" are
+// ignored.
+func (p *NumberingPlan) Load(r io.Reader) error {
+	prefixes := &numberingPlanPrefixes{types: make(map[string]domain.PhoneType)}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return fmt.Errorf("line %d: expected \"<prefix> <type>\"", line)
+		}
+		prefix, ok := strings.CutPrefix(fields[0], "+")
+		if !ok || prefix == "" || strings.Trim(prefix, "0123456789") != "" {
+			return fmt.Errorf("line %d: prefix %q is not \"+\" followed by digits", line, fields[0])
+		}
+		typ := domain.PhoneType(fields[1])
+		if !typ.Valid() {
+			return fmt.Errorf("line %d: unknown phone type %q", line, fields[1])
+		}
+		if _, dup := prefixes.types[prefix]; dup {
+			return fmt.Errorf("line %d: duplicate prefix %s", line, fields[0])
+		}
+
+		prefixes.types[prefix] = typ
+		prefixes.longest = max(prefixes.longest, len(prefix))
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read numbering plan: %w", err)
+	}
+
+	p.prefixes.Store(prefixes)
+	return nil
+}
+
+// Len returns the number of prefixes in the dataset.
+func (p *NumberingPlan) Len() int {
+	prefixes := p.prefixes.Load()
+	if prefixes == nil {
+		return 0
+	}
+
+	return len(prefixes.types)
+}
+
+func (p *NumberingPlan) Classify(_ context.Context, e164 string) (domain.PhoneType, error) {
+	prefixes := p.prefixes.Load()
+	if prefixes == nil {
+		return domain.PhoneTypeUnknown, nil
+	}
+
+	digits := strings.TrimPrefix(e164, "+")
+	for n := min(prefixes.longest, len(digits)); n > 0; n-- {
+		if typ, ok := prefixes.types[digits[:n]]; ok {
+			return typ, nil
+		}
+	}
+
+	return domain.PhoneTypeUnknown, nil
+}
+
+// ParsePhoneTypes parses a list of type names such as "voip", as used by
+// PhoneTypePolicy.
+func ParsePhoneTypes(names []string) ([]domain.PhoneType, error) {
+	types := make([]domain.PhoneType, 0, len(names))
+	for _, name := range names {
+		typ := domain.PhoneType(strings.ToLower(strings.TrimSpace(name)))
+		if !typ.Valid() {
+			return nil, fmt.Errorf("unknown phone type %q", name)
+		}
+		types = append(types, typ)
+	}
+
+	return types, nil
+}
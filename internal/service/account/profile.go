@@ -0,0 +1,145 @@
+package account
+
+import (
+	"net/url"
+	"strings"
+	"time"
+	_ "time/tzdata" // validate zones against the embedded IANA database, not the host's
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+// Limits of profile fields: display names and bios in characters, avatar
+// URLs in bytes.
+const (
+	MaxDisplayNameLength = 64
+	MaxBioLength         = 280
+	MaxAvatarURLLength   = 2048
+)
+
+const maxLocaleLength = 35
+
+// avatarURLSchemes lists the schemes an avatar URL may use. Clients render
+// avatars directly, so anything that is not fetched over TLS is rejected.
+var avatarURLSchemes = map[string]bool{
+	"https": true,
+}
+
+// normalizeProfileUpdate validates the set fields of update and returns them
+// in canonical form. Empty values are kept, as they clear the field.
+func normalizeProfileUpdate(update domain.ProfileUpdate) (domain.ProfileUpdate, error) {
+	fields := []struct {
+		value     *string
+		normalize func(string) (string, bool)
+		err       error
+	}{
+		{update.DisplayName, normalizeDisplayName, domain.ErrInvalidDisplayName},
+		{update.Bio, normalizeBio, domain.ErrInvalidBio},
+		{update.AvatarURL, normalizeAvatarURL, domain.ErrInvalidAvatarURL},
+		{update.Locale, normalizeLocale, domain.ErrInvalidLocale},
+		{update.TimeZone, normalizeTimeZone, domain.ErrInvalidTimeZone},
+	}
+
+	out := make([]*string, len(fields))
+	for i, f := range fields {
+		if f.value == nil {
+			continue
+		}
+
+		value := strings.TrimSpace(*f.value)
+		if value != "" {
+			var ok bool
+			if value, ok = f.normalize(value); !ok {
+				return domain.ProfileUpdate{}, f.err
+			}
+		}
+		out[i] = &value
+	}
+
+	return domain.ProfileUpdate{
+		DisplayName: out[0],
+		Bio:         out[1],
+		AvatarURL:   out[2],
+		Locale:      out[3],
+		TimeZone:    out[4],
+	}, nil
+}
+
+func normalizeDisplayName(name string) (string, bool) {
+	return normalizeText(name, MaxDisplayNameLength, false)
+}
+
+func normalizeBio(bio string) (string, bool) {
+	return normalizeText(bio, MaxBioLength, true)
+}
+
+// normalizeText converts s to NFC, so visually identical strings compare and
+// count equal, and rejects control characters other than newlines in
+// multiline text.
+func normalizeText(s string, maxLength int, multiline bool) (string, bool) {
+	if !utf8.ValidString(s) {
+		return "", false
+	}
+
+	s = norm.NFC.String(s)
+	if utf8.RuneCountInString(s) > maxLength {
+		return "", false
+	}
+
+	for _, r := range s {
+		if unicode.IsControl(r) && (!multiline || r != '\n') {
+			return "", false
+		}
+	}
+
+	return s, true
+}
+
+func normalizeAvatarURL(raw string) (string, bool) {
+	if len(raw) > MaxAvatarURLLength {
+		return "", false
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || !avatarURLSchemes[strings.ToLower(u.Scheme)] || u.Host == "" || u.User != nil {
+		return "", false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	return u.String(), true
+}
+
+// normalizeLocale returns the canonical BCP 47 form of tag, e.g. "en-US" for
+// "en_us".
+func normalizeLocale(tag string) (string, bool) {
+	if len(tag) > maxLocaleLength {
+		return "", false
+	}
+
+	t, err := language.Parse(strings.ReplaceAll(tag, "_", "-"))
+	if err != nil || t == language.Und {
+		return "", false
+	}
+
+	return t.String(), true
+}
+
+// normalizeTimeZone accepts IANA zone names. "Local" is rejected because it
+// names the server's zone rather than the user's.
+func normalizeTimeZone(name string) (string, bool) {
+	if name == "Local" {
+		return "", false
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return "", false
+	}
+
+	return loc.String(), true
+}
@@ -2,67 +2,213 @@ package account
 
 import (
 	"context"
-	"crypto/rand"
+	"errors"
+	"fmt"
 	"regexp"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/telemetry"
 )
 
-const maxNickGenerationAttempts = 10
+// statusTransitions lists, for each status an account can be moved to by a
+// moderator, the statuses it may be moved from. Deletion is handled by Delete
+// and is allowed from any status.
+var statusTransitions = map[domain.Status][]domain.Status{
+	domain.StatusSuspended: {domain.StatusActive},
+	domain.StatusActive:    {domain.StatusSuspended},
+	domain.StatusBanned:    {domain.StatusActive, domain.StatusSuspended},
+}
 
-var (
-	nickPattern  = regexp.MustCompile(`^@[a-zA-Z0-9_]{2,30}$`)
-	phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+// Length limits of a nick after its "@".
+const (
+	MinNickLength = 2
+	MaxNickLength = 30
 )
 
+var nickPattern = regexp.MustCompile(fmt.Sprintf(`^@[a-zA-Z0-9_]{%d,%d}$`, MinNickLength, MaxNickLength))
+
 type Repository interface {
-	Create(ctx context.Context, id uuid.UUID, nick, phone string) (domain.Account, error)
+	Create(ctx context.Context, id uuid.UUID, nick string, phone domain.Phone, cooldown time.Duration) (domain.Account, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain.Account, error)
-	UpdateNick(ctx context.Context, id uuid.UUID, nick string) (domain.Account, error)
+	GetByNick(ctx context.Context, nick string, redirectPeriod time.Duration) (domain.Account, error)
+	ListNickHistory(ctx context.Context, id uuid.UUID) ([]domain.NickChange, error)
+	// CheckNicks reports, for those of nicks that account id could not claim
+	// given cooldown, domain.NickCheckTaken or domain.NickCheckCooldown. Keys
+	// are lowercase; nicks held or released by id itself are claimable, and
+	// uuid.Nil stands for a new account.
+	CheckNicks(ctx context.Context, id uuid.UUID, nicks []string, cooldown time.Duration) (map[string]domain.NickCheckReason, error)
+	UpdateNick(ctx context.Context, id uuid.UUID, nick string, cooldown time.Duration) (domain.Account, error)
+	UpdateProfile(ctx context.Context, id uuid.UUID, update domain.ProfileUpdate) (domain.Account, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, to domain.Status, reason domain.StatusReason, from ...domain.Status) (domain.Account, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	ConsistencyToken(ctx context.Context) (string, error)
 }
 
 type Service struct {
-	repo Repository
+	repo      Repository
+	metrics   *telemetry.Metrics
+	reads     singleflight.Group
+	avatars   BlobStore
+	avatarCfg AvatarConfig
+	nicks     NickHistoryConfig
+
+	nickRuleStore NickRuleStore
+	nickRules     atomic.Pointer[nickRuleSet]
+	nickFilter    NickFilter
+
+	nickGen         NickGenerator
+	nickGenAttempts int
+
+	phoneRegion     string
+	phoneClassifier PhoneClassifier
+	phonePolicy     PhoneTypePolicy
+
+	velocity    VelocityStore
+	velocityCfg SignupVelocityConfig
 }
 
-func New(repo Repository) *Service {
-	return &Service{repo: repo}
+type Option func(*Service)
+
+func WithMetrics(metrics *telemetry.Metrics) Option {
+	return func(s *Service) {
+		s.metrics = metrics
+	}
 }
 
-func (s *Service) Create(ctx context.Context, phone string) (domain.Account, error) {
-	phone = strings.TrimSpace(phone)
-	if !isValidPhone(phone) {
-		return domain.Account{}, domain.ErrInvalidPhone
+func New(repo Repository, opts ...Option) *Service {
+	s := &Service{
+		repo: repo,
+		nicks: NickHistoryConfig{
+			RedirectPeriod: defaultNickRedirectPeriod,
+			Cooldown:       defaultNickCooldown,
+		},
+		nickGen:         NewRandomNickGenerator(0),
+		nickGenAttempts: defaultNickGenerationAttempts,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Create creates an account with the requested nick, claimed in the same
+// transaction that inserts the account, or with a generated one when no nick
+// is requested. If the requested nick is reserved, filtered, taken or in
+// cooldown, Create fails unless req.GenerateNickIfUnavailable is set.
+func (s *Service) Create(ctx context.Context, req domain.NewAccount) (domain.Account, error) {
+	e164, err := s.normalizePhone(req.Phone)
+	if err != nil {
+		return domain.Account{}, err
+	}
+	locale := strings.TrimSpace(req.Locale)
+	if locale != "" {
+		var ok bool
+		if locale, ok = normalizeLocale(locale); !ok {
+			return domain.Account{}, domain.ErrInvalidLocale
+		}
+	}
+	nick := strings.TrimSpace(req.Nick)
+	if nick != "" && !isValidNick(nick) {
+		return domain.Account{}, domain.ErrInvalidNick
+	}
+
+	if err = s.checkSignupVelocity(ctx, e164); err != nil {
+		return domain.Account{}, err
+	}
+	phone, err := s.classifyPhone(ctx, e164)
+	if err != nil {
+		return domain.Account{}, err
 	}
 
 	id := uuid.New()
-	for range maxNickGenerationAttempts {
-		nick, err := generateNick()
+	if nick != "" {
+		acc, err := s.createWithNick(ctx, id, nick, phone)
+		if err == nil || !req.GenerateNickIfUnavailable || !isNickUnavailable(err) {
+			return acc, err
+		}
+	}
+
+	return s.createWithGeneratedNick(ctx, id, phone, locale)
+}
+
+func (s *Service) createWithNick(ctx context.Context, id uuid.UUID, nick string, phone domain.Phone) (domain.Account, error) {
+	if err := s.checkNickContent(ctx, nick); err != nil {
+		return domain.Account{}, err
+	}
+
+	return s.repo.Create(ctx, id, nick, phone, s.nicks.Cooldown)
+}
+
+// createWithGeneratedNick replaces proposals that are reserved, filtered or
+// taken until one is claimed or the attempts run out.
+func (s *Service) createWithGeneratedNick(ctx context.Context, id uuid.UUID, phone domain.Phone, locale string) (domain.Account, error) {
+	collisions := 0
+	for range s.nickGenAttempts {
+		nick, err := s.nickGen.Generate(ctx, locale, collisions)
 		if err != nil {
 			return domain.Account{}, err
 		}
 
-		acc, err := s.repo.Create(ctx, id, nick, phone)
-		if err == nil {
+		acc, err := s.createWithNick(ctx, id, nick, phone)
+		switch {
+		case err == nil:
+			s.metrics.IncNickGeneration(telemetry.NickGenerationClaimed)
 			return acc, nil
+		case isNickRejected(err):
+			s.metrics.IncNickGeneration(telemetry.NickGenerationRejected)
+		case isNickTaken(err):
+			s.metrics.IncNickGeneration(telemetry.NickGenerationCollision)
+			collisions++
+		default:
+			return domain.Account{}, err
 		}
-
-		if err == domain.ErrNickAlreadyExists {
-			continue
-		}
-
-		return domain.Account{}, err
 	}
 
+	s.metrics.IncNickGeneration(telemetry.NickGenerationExhausted)
 	return domain.Account{}, domain.ErrNickAlreadyExists
 }
 
+// GetByID coalesces concurrent reads of the same account into one repository
+// call. Reads are only shared between callers with the same consistency
+// token. The shared call outlives a caller that gives up early, but not the
+// deadline of the caller that started it.
 func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (domain.Account, error) {
-	return s.repo.GetByID(ctx, id)
+	key := id.String() + "/" + domain.ConsistencyTokenFrom(ctx)
+
+	leader := false
+	ch := s.reads.DoChan(key, func() (any, error) {
+		leader = true
+
+		readCtx := context.WithoutCancel(ctx)
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			readCtx, cancel = context.WithDeadline(readCtx, deadline)
+			defer cancel()
+		}
+
+		return s.repo.GetByID(readCtx, id)
+	})
+
+	select {
+	case <-ctx.Done():
+		return domain.Account{}, ctx.Err()
+	case res := <-ch:
+		if !leader {
+			s.metrics.IncCoalesced("get_by_id")
+		}
+		if res.Err != nil {
+			return domain.Account{}, res.Err
+		}
+
+		return res.Val.(domain.Account), nil
+	}
 }
 
 func (s *Service) UpdateNick(ctx context.Context, id uuid.UUID, nick string) (domain.Account, error) {
@@ -70,32 +216,85 @@ func (s *Service) UpdateNick(ctx context.Context, id uuid.UUID, nick string) (do
 	if !isValidNick(nick) {
 		return domain.Account{}, domain.ErrInvalidNick
 	}
+	if err := s.checkNickContent(ctx, nick); err != nil {
+		return domain.Account{}, err
+	}
 
-	return s.repo.UpdateNick(ctx, id, nick)
+	return s.repo.UpdateNick(ctx, id, nick, s.nicks.Cooldown)
 }
 
-func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+// UpdateProfile validates and normalizes the set fields of update and applies
+// them to an active account.
+func (s *Service) UpdateProfile(ctx context.Context, id uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
+	update, err := normalizeProfileUpdate(update)
+	if err != nil {
+		return domain.Account{}, err
+	}
+	if update.AvatarURL != nil {
+		// A thumbnail only exists for uploaded avatars.
+		noThumbnail := ""
+		update.AvatarThumbnailURL = &noThumbnail
+	}
+
+	return s.repo.UpdateProfile(ctx, id, update)
 }
 
-func isValidNick(nick string) bool {
-	return nickPattern.MatchString(nick)
+// Suspend temporarily blocks an active account from being modified.
+func (s *Service) Suspend(ctx context.Context, id uuid.UUID, reason domain.StatusReason) (domain.Account, error) {
+	return s.transition(ctx, id, domain.StatusSuspended, reason)
 }
 
-func isValidPhone(phone string) bool {
-	return phonePattern.MatchString(phone)
+// Reinstate returns a suspended account to active. Bans are permanent.
+func (s *Service) Reinstate(ctx context.Context, id uuid.UUID, reason domain.StatusReason) (domain.Account, error) {
+	return s.transition(ctx, id, domain.StatusActive, reason)
 }
 
-func generateNick() (string, error) {
-	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
-	buf := make([]byte, 10)
-	if _, err := rand.Read(buf); err != nil {
-		return "", err
+// Ban permanently blocks an active or suspended account.
+func (s *Service) Ban(ctx context.Context, id uuid.UUID, reason domain.StatusReason) (domain.Account, error) {
+	return s.transition(ctx, id, domain.StatusBanned, reason)
+}
+
+func (s *Service) transition(ctx context.Context, id uuid.UUID, to domain.Status, reason domain.StatusReason) (domain.Account, error) {
+	if !reason.Valid() {
+		return domain.Account{}, domain.ErrInvalidStatusReason
 	}
 
-	for i := range buf {
-		buf[i] = alphabet[int(buf[i])%len(alphabet)]
+	return s.repo.UpdateStatus(ctx, id, to, reason, statusTransitions[to]...)
+}
+
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ConsistencyToken returns a token that, passed back on reads via
+// domain.WithConsistencyToken, guarantees they observe all mutations
+// completed before it was issued.
+func (s *Service) ConsistencyToken(ctx context.Context) (string, error) {
+	return s.repo.ConsistencyToken(ctx)
+}
+
+// checkNickContent applies the reserved nick rules and the content filter to
+// a syntactically valid nick.
+func (s *Service) checkNickContent(ctx context.Context, nick string) error {
+	if err := s.checkNickRules(nick); err != nil {
+		return err
 	}
 
-	return "@" + string(buf), nil
+	return s.checkNickFilter(ctx, nick)
+}
+
+// isNickTaken reports whether err means another account holds or recently
+// released the nick.
+func isNickTaken(err error) bool {
+	return errors.Is(err, domain.ErrNickAlreadyExists) || errors.Is(err, domain.ErrNickInCooldown)
+}
+
+// isNickUnavailable reports whether err means the nick cannot be claimed, as
+// opposed to failing to try.
+func isNickUnavailable(err error) bool {
+	return isNickRejected(err) || isNickTaken(err)
+}
+
+func isValidNick(nick string) bool {
+	return nickPattern.MatchString(nick)
 }
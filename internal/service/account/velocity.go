@@ -0,0 +1,212 @@
+package account
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kvetinski/account/internal/domain"
+)
+
+// Scopes of signup velocity limits, used in domain.RateLimitedError and to
+// label metrics.
+const (
+	VelocityScopePhonePrefix = "phone_prefix"
+	VelocityScopeCaller      = "caller"
+	VelocityScopeIP          = "ip"
+)
+
+const defaultVelocityPrefixDigits = 3
+
+// VelocityStore counts events per key in fixed windows, from which the
+// service estimates a sliding window.
+type VelocityStore interface {
+	// Hit records one event for key at now and returns the count of the
+	// window of length window containing now, including this event, and of
+	// the window before it.
+	Hit(ctx context.Context, key string, window time.Duration, now time.Time) (VelocityCount, error)
+}
+
+// VelocityCount is what VelocityStore.Hit returns.
+type VelocityCount struct {
+	// WindowStart is the start of the current window, now truncated to the
+	// window length.
+	WindowStart time.Time
+	Current     int64
+	Previous    int64
+}
+
+// VelocityLimit allows Limit signups per sliding Window. A zero Limit
+// disables it.
+type VelocityLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// SignupVelocityConfig limits how fast accounts are created per scope.
+type SignupVelocityConfig struct {
+	// PhonePrefix limits signups for numbers that only differ in their last
+	// PhonePrefixDigits digits, so that bursts of sequential numbers from one
+	// range are caught.
+	PhonePrefix       VelocityLimit
+	PhonePrefixDigits int
+	// Caller limits signups per domain.Caller ID. Unless clients
+	// authenticate with TLS certificates, the ID is whatever the client
+	// claims, so this limit only holds for well-behaved callers.
+	Caller VelocityLimit
+	// IP limits signups per caller IPv4 address or IPv6 /64.
+	IP VelocityLimit
+}
+
+// WithSignupVelocity limits how fast Create may be called per phone prefix,
+// caller and IP. Attempts count whether they succeed or not, so a caller
+// retrying a rejected signup stays limited.
+func WithSignupVelocity(store VelocityStore, cfg SignupVelocityConfig) Option {
+	return func(s *Service) {
+		if cfg.PhonePrefixDigits <= 0 {
+			cfg.PhonePrefixDigits = defaultVelocityPrefixDigits
+		}
+
+		s.velocity = store
+		s.velocityCfg = cfg
+	}
+}
+
+// checkSignupVelocity counts a signup for e164 and the caller in ctx against
+// every configured limit.
+func (s *Service) checkSignupVelocity(ctx context.Context, e164 string) error {
+	if s.velocity == nil {
+		return nil
+	}
+
+	caller := domain.CallerFrom(ctx)
+	checks := []struct {
+		scope, value string
+		limit        VelocityLimit
+	}{
+		{VelocityScopePhonePrefix, e164[:max(len(e164)-s.velocityCfg.PhonePrefixDigits, 1)], s.velocityCfg.PhonePrefix},
+		{VelocityScopeCaller, velocityCallerKey(caller.ID), s.velocityCfg.Caller},
+		{VelocityScopeIP, caller.IPBlock(), s.velocityCfg.IP},
+	}
+
+	now := time.Now()
+	for _, c := range checks {
+		if c.value == "" || c.limit.Limit <= 0 || c.limit.Window <= 0 {
+			continue
+		}
+
+		count, err := s.velocity.Hit(ctx, "signup:"+c.scope+":"+c.value, c.limit.Window, now)
+		if err != nil {
+			return fmt.Errorf("check signup velocity: %w", err)
+		}
+
+		if retryAfter := velocityRetryAfter(count, c.limit, now); retryAfter > 0 {
+			s.metrics.IncVelocityRejection(c.scope)
+			return &domain.RateLimitedError{Scope: c.scope, RetryAfter: retryAfter}
+		}
+	}
+
+	return nil
+}
+
+// velocityRetryAfter returns zero if the sliding window estimate of count is
+// within limit, or else how long until another event would be.
+//
+// The estimate weights the previous window by how much of it still overlaps
+// the sliding window ending at now.
+func velocityRetryAfter(count VelocityCount, limit VelocityLimit, now time.Time) time.Duration {
+	window := float64(limit.Window)
+	elapsed := float64(now.Sub(count.WindowStart))
+	prev, curr := float64(count.Previous), float64(count.Current)
+	if prev*(1-elapsed/window)+curr <= float64(limit.Limit) {
+		return 0
+	}
+
+	// The next event fits once the other events weigh at most limit-1.
+	target := float64(limit.Limit - 1)
+	var wait float64
+	if curr <= target {
+		wait = window*(1-(target-curr)/prev) - elapsed
+	} else {
+		// Only once the current window has become the previous one.
+		wait = window - elapsed + window*(1-target/curr)
+	}
+
+	return time.Duration(math.Ceil(max(wait, float64(time.Millisecond))/float64(time.Millisecond))) * time.Millisecond
+}
+
+// maxVelocityCallerKeyLength keeps counter keys within the 128 bytes of
+// velocity_counters.key.
+const maxVelocityCallerKeyLength = 64
+
+// velocityCallerKey returns id, or its SHA-256 if it is longer than
+// maxVelocityCallerKeyLength. Caller IDs come from request metadata or a TLS
+// URI SAN and have no length limit of their own.
+func velocityCallerKey(id string) string {
+	if len(id) <= maxVelocityCallerKeyLength {
+		return id
+	}
+
+	sum := sha256.Sum256([]byte(id))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// MemoryVelocityStore is a VelocityStore local to one process.
+type MemoryVelocityStore struct {
+	mu        sync.Mutex
+	counters  map[string]*memoryVelocityCounter
+	lastSweep time.Time
+}
+
+type memoryVelocityCounter struct {
+	windowStart       time.Time
+	current, previous int64
+	window            time.Duration
+}
+
+var _ VelocityStore = (*MemoryVelocityStore)(nil)
+
+func NewMemoryVelocityStore() *MemoryVelocityStore {
+	return &MemoryVelocityStore{counters: make(map[string]*memoryVelocityCounter)}
+}
+
+func (m *MemoryVelocityStore) Hit(_ context.Context, key string, window time.Duration, now time.Time) (VelocityCount, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	start := now.Truncate(window)
+	c, ok := m.counters[key]
+	switch {
+	case !ok:
+		c = &memoryVelocityCounter{windowStart: start, window: window}
+		m.counters[key] = c
+	case start.Equal(c.windowStart.Add(window)):
+		c.windowStart, c.previous, c.current = start, c.current, 0
+	case !start.Equal(c.windowStart):
+		c.windowStart, c.previous, c.current = start, 0, 0
+	}
+	c.current++
+
+	return VelocityCount{WindowStart: c.windowStart, Current: c.current, Previous: c.previous}, nil
+}
+
+// sweep drops counters that no longer affect any estimate, at most once a
+// minute.
+func (m *MemoryVelocityStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+
+	for key, c := range m.counters {
+		if now.Sub(c.windowStart) >= 2*c.window {
+			delete(m.counters, key)
+		}
+	}
+}
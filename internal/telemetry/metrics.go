@@ -5,16 +5,60 @@ import (
 	"errors"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// Database roles used to label DB metrics.
+const (
+	DBRolePrimary = "primary"
+	DBRoleReplica = "replica"
+)
+
+// Outcomes of a generated nick, used to label nick generation metrics.
+const (
+	NickGenerationClaimed   = "claimed"
+	NickGenerationCollision = "collision"
+	NickGenerationRejected  = "rejected"
+	// NickGenerationExhausted counts accounts that could not be created
+	// because every proposal failed.
+	NickGenerationExhausted = "exhausted"
+)
+
+// Actions taken on a classified phone number, used to label phone
+// classification metrics.
+const (
+	PhoneClassificationAccepted = "accepted"
+	PhoneClassificationFlagged  = "flagged"
+	PhoneClassificationRejected = "rejected"
+)
+
+// What triggered a failed nick rule refresh, used to label its metric.
+const (
+	NickRuleRefreshWrite = "write"
+	NickRuleRefreshSync  = "sync"
+)
+
 type Metrics struct {
 	grpcRequestsTotal    *prometheus.CounterVec
 	grpcRequestDuration  *prometheus.HistogramVec
 	grpcRequestsInFlight prometheus.Gauge
+	grpcRateLimitedTotal *prometheus.CounterVec
+	grpcConcurrencyLimit prometheus.Gauge
 
 	dbQueriesTotal  *prometheus.CounterVec
 	dbQueryDuration *prometheus.HistogramVec
+
+	cacheRequestsTotal      *prometheus.CounterVec
+	cacheInvalidationsTotal *prometheus.CounterVec
+
+	coalescedRequestsTotal *prometheus.CounterVec
+
+	nickGenerationsTotal         *prometheus.CounterVec
+	nickRuleRefreshFailuresTotal *prometheus.CounterVec
+
+	phoneClassificationsTotal *prometheus.CounterVec
+	velocityRejectionsTotal   *prometheus.CounterVec
 }
 
 func NewMetrics(registerer prometheus.Registerer) *Metrics {
@@ -44,20 +88,82 @@ func NewMetrics(registerer prometheus.Registerer) *Metrics {
 				Help: "Current number of in-flight gRPC requests.",
 			},
 		),
+		grpcRateLimitedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "account_grpc_rate_limited_total",
+				Help: "Total gRPC requests rejected by the rate limiter by method and scope.",
+			},
+			[]string{"method", "scope"},
+		),
+		grpcConcurrencyLimit: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "account_grpc_concurrency_limit",
+				Help: "Current adaptive limit of in-flight gRPC requests.",
+			},
+		),
 		dbQueriesTotal: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "account_db_queries_total",
-				Help: "Total DB method calls by method and status.",
+				Help: "Total DB method calls by method, target role and status.",
 			},
-			[]string{"method", "status"},
+			[]string{"method", "role", "status"},
 		),
 		dbQueryDuration: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    "account_db_query_duration_seconds",
-				Help:    "DB method duration in seconds by method and status.",
+				Help:    "DB method duration in seconds by method, target role and status.",
 				Buckets: prometheus.DefBuckets,
 			},
-			[]string{"method", "status"},
+			[]string{"method", "role", "status"},
+		),
+		cacheRequestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "account_cache_requests_total",
+				Help: "Total account cache lookups by cache tier and result.",
+			},
+			[]string{"cache", "result"},
+		),
+		cacheInvalidationsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "account_cache_invalidations_total",
+				Help: "Total account cache invalidations by source.",
+			},
+			[]string{"source"},
+		),
+		coalescedRequestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "account_coalesced_requests_total",
+				Help: "Total service reads answered by an identical in-flight read instead of the repository.",
+			},
+			[]string{"method"},
+		),
+		nickGenerationsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "account_nick_generations_total",
+				Help: "Total generated nick proposals by result, and account creations that ran out of proposals.",
+			},
+			[]string{"result"},
+		),
+		nickRuleRefreshFailuresTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "account_nick_rule_refresh_failures_total",
+				Help: "Total failed reloads of the nick rules, after a rule change on this replica or by the periodic sync.",
+			},
+			[]string{"trigger"},
+		),
+		phoneClassificationsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "account_phone_classifications_total",
+				Help: "Total phone numbers of new accounts by type and the action the policy took.",
+			},
+			[]string{"type", "action"},
+		),
+		velocityRejectionsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "account_signup_velocity_rejections_total",
+				Help: "Total signups rejected by velocity limits by scope.",
+			},
+			[]string{"scope"},
 		),
 	}
 
@@ -65,8 +171,17 @@ func NewMetrics(registerer prometheus.Registerer) *Metrics {
 		m.grpcRequestsTotal,
 		m.grpcRequestDuration,
 		m.grpcRequestsInFlight,
+		m.grpcRateLimitedTotal,
+		m.grpcConcurrencyLimit,
 		m.dbQueriesTotal,
 		m.dbQueryDuration,
+		m.cacheRequestsTotal,
+		m.cacheInvalidationsTotal,
+		m.coalescedRequestsTotal,
+		m.nickGenerationsTotal,
+		m.nickRuleRefreshFailuresTotal,
+		m.phoneClassificationsTotal,
+		m.velocityRejectionsTotal,
 	)
 
 	return m
@@ -97,13 +212,85 @@ func (m *Metrics) DecRPCInFlight() {
 	m.grpcRequestsInFlight.Dec()
 }
 
-func (m *Metrics) ObserveDB(method, status string, duration time.Duration) {
+func (m *Metrics) ObserveDB(method, role, status string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.dbQueriesTotal.WithLabelValues(method, role, status).Inc()
+	m.dbQueryDuration.WithLabelValues(method, role, status).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveCache(cache, result string) {
+	if m == nil {
+		return
+	}
+
+	m.cacheRequestsTotal.WithLabelValues(cache, result).Inc()
+}
+
+func (m *Metrics) IncCacheInvalidation(source string) {
+	if m == nil {
+		return
+	}
+
+	m.cacheInvalidationsTotal.WithLabelValues(source).Inc()
+}
+
+func (m *Metrics) IncCoalesced(method string) {
+	if m == nil {
+		return
+	}
+
+	m.coalescedRequestsTotal.WithLabelValues(method).Inc()
+}
+
+func (m *Metrics) IncNickGeneration(result string) {
+	if m == nil {
+		return
+	}
+
+	m.nickGenerationsTotal.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) IncNickRuleRefreshFailure(trigger string) {
 	if m == nil {
 		return
 	}
 
-	m.dbQueriesTotal.WithLabelValues(method, status).Inc()
-	m.dbQueryDuration.WithLabelValues(method, status).Observe(duration.Seconds())
+	m.nickRuleRefreshFailuresTotal.WithLabelValues(trigger).Inc()
+}
+
+func (m *Metrics) IncPhoneClassification(phoneType, action string) {
+	if m == nil {
+		return
+	}
+
+	m.phoneClassificationsTotal.WithLabelValues(phoneType, action).Inc()
+}
+
+func (m *Metrics) IncRateLimited(method, scope string) {
+	if m == nil {
+		return
+	}
+
+	m.grpcRateLimitedTotal.WithLabelValues(method, scope).Inc()
+}
+
+func (m *Metrics) SetConcurrencyLimit(limit float64) {
+	if m == nil {
+		return
+	}
+
+	m.grpcConcurrencyLimit.Set(limit)
+}
+
+func (m *Metrics) IncVelocityRejection(scope string) {
+	if m == nil {
+		return
+	}
+
+	m.velocityRejectionsTotal.WithLabelValues(scope).Inc()
 }
 
 func RegisterDBPoolMetrics(db *sql.DB, registerer prometheus.Registerer) error {
@@ -166,6 +353,92 @@ func RegisterDBPoolMetrics(db *sql.DB, registerer prometheus.Registerer) error {
 		),
 	}
 
+	return registerCollectors(registerer, collectors)
+}
+
+// RegisterPgxPoolMetrics exports pgxpool statistics under the same metric
+// names as RegisterDBPoolMetrics so dashboards work for either driver. Each
+// pool is told apart by its role ("primary" or "replica") and name.
+func RegisterPgxPoolMetrics(pool *pgxpool.Pool, role, name string, registerer prometheus.Registerer) error {
+	if pool == nil {
+		return errors.New("pool is nil")
+	}
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	labels := prometheus.Labels{"role": role, "pool": name}
+
+	collectors := []prometheus.Collector{
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name:        "account_db_pool_open_connections",
+				Help:        "Open database connections.",
+				ConstLabels: labels,
+			},
+			func() float64 { return float64(pool.Stat().TotalConns()) },
+		),
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name:        "account_db_pool_in_use_connections",
+				Help:        "In-use database connections.",
+				ConstLabels: labels,
+			},
+			func() float64 { return float64(pool.Stat().AcquiredConns()) },
+		),
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name:        "account_db_pool_idle_connections",
+				Help:        "Idle database connections.",
+				ConstLabels: labels,
+			},
+			func() float64 { return float64(pool.Stat().IdleConns()) },
+		),
+		prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name:        "account_db_pool_max_connections",
+				Help:        "Maximum size of the database pool.",
+				ConstLabels: labels,
+			},
+			func() float64 { return float64(pool.Stat().MaxConns()) },
+		),
+		prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Name:        "account_db_pool_wait_count_total",
+				Help:        "Total number of waits for a free connection.",
+				ConstLabels: labels,
+			},
+			func() float64 { return float64(pool.Stat().EmptyAcquireCount()) },
+		),
+		prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Name:        "account_db_pool_wait_duration_seconds_total",
+				Help:        "Total time blocked waiting for a free connection in seconds.",
+				ConstLabels: labels,
+			},
+			func() float64 { return pool.Stat().EmptyAcquireWaitTime().Seconds() },
+		),
+		prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Name:        "account_db_pool_max_idle_closed_total",
+				Help:        "Total connections closed due to MaxConnIdleTime.",
+				ConstLabels: labels,
+			},
+			func() float64 { return float64(pool.Stat().MaxIdleDestroyCount()) },
+		),
+		prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Name:        "account_db_pool_max_lifetime_closed_total",
+				Help:        "Total connections closed due to MaxConnLifetime.",
+				ConstLabels: labels,
+			},
+			func() float64 { return float64(pool.Stat().MaxLifetimeDestroyCount()) },
+		),
+	}
+
+	return registerCollectors(registerer, collectors)
+}
+
+func registerCollectors(registerer prometheus.Registerer, collectors []prometheus.Collector) error {
 	for _, c := range collectors {
 		if err := registerer.Register(c); err != nil {
 			var already prometheus.AlreadyRegisteredError
@@ -0,0 +1,4 @@
+ALTER TABLE accounts
+    DROP CONSTRAINT IF EXISTS accounts_status_check,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status;
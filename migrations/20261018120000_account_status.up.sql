@@ -0,0 +1,8 @@
+ALTER TABLE accounts
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active',
+    ADD COLUMN status_reason VARCHAR(32) NOT NULL DEFAULT '';
+
+UPDATE accounts SET status = 'deleted' WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_status_check CHECK (status IN ('active', 'suspended', 'banned', 'deleted'));
@@ -0,0 +1,6 @@
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS time_zone,
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS display_name;
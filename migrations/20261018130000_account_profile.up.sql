@@ -0,0 +1,6 @@
+ALTER TABLE accounts
+    ADD COLUMN display_name VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN bio VARCHAR(280) NOT NULL DEFAULT '',
+    ADD COLUMN avatar_url VARCHAR(2048) NOT NULL DEFAULT '',
+    ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '',
+    ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT '';
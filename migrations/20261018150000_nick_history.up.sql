@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS nick_history (
+    id BIGSERIAL PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts (id),
+    nick VARCHAR(31) NOT NULL,
+    released_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS nick_history_nick_released_at_idx ON nick_history (nick, released_at DESC);
+CREATE INDEX IF NOT EXISTS nick_history_account_id_idx ON nick_history (account_id, released_at DESC);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS nick_history_nick_key_released_at_idx;
+CREATE INDEX IF NOT EXISTS nick_history_nick_released_at_idx ON nick_history (nick, released_at DESC);
+
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_nick_key UNIQUE (nick),
+    DROP COLUMN nick_key;
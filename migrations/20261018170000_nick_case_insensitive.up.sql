@@ -0,0 +1,30 @@
+-- Nicks that differ only in case cannot coexist once uniqueness ignores case.
+-- Resolve them first, e.g. by renaming all but the oldest account; this lists
+-- them and aborts otherwise.
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(format('%s: %s', nick_key, holders), '; ' ORDER BY nick_key)
+    INTO collisions
+    FROM (
+        SELECT lower(nick) AS nick_key,
+               string_agg(format('%s (%s)', nick, id), ', ' ORDER BY created_at) AS holders
+        FROM accounts
+        GROUP BY lower(nick)
+        HAVING COUNT(*) > 1
+    ) c;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'nicks differing only in case must be resolved before making nicks case-insensitive: %', collisions;
+    END IF;
+END
+$$;
+
+ALTER TABLE accounts
+    ADD COLUMN nick_key VARCHAR(31) GENERATED ALWAYS AS (lower(nick)) STORED,
+    ADD CONSTRAINT accounts_nick_key_key UNIQUE (nick_key),
+    DROP CONSTRAINT accounts_nick_key;
+
+DROP INDEX IF EXISTS nick_history_nick_released_at_idx;
+CREATE INDEX IF NOT EXISTS nick_history_nick_key_released_at_idx ON nick_history (lower(nick), released_at DESC);
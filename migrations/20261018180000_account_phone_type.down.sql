@@ -0,0 +1,3 @@
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS phone_flagged,
+    DROP COLUMN IF EXISTS phone_type;
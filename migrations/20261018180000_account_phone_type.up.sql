@@ -0,0 +1,3 @@
+ALTER TABLE accounts
+    ADD COLUMN phone_type VARCHAR(16) NOT NULL DEFAULT 'unknown',
+    ADD COLUMN phone_flagged BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS velocity_counters (
+    key VARCHAR(128) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    hits BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key, window_start)
+);
+
+CREATE INDEX IF NOT EXISTS velocity_counters_expires_at_idx ON velocity_counters (expires_at);
@@ -0,0 +1,9 @@
+package migrations
+
+import "embed"
+
+// SQLite holds the SQLite schema migrations. The Postgres migrations next to
+// this file are applied by the migrate job and are not embedded.
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS accounts (
+    id TEXT PRIMARY KEY,
+    nick VARCHAR(31) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT accounts_nick_key UNIQUE (nick),
+    CONSTRAINT accounts_phone_key UNIQUE (phone)
+);
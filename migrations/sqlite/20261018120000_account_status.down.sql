@@ -0,0 +1,2 @@
+ALTER TABLE accounts DROP COLUMN status_reason;
+ALTER TABLE accounts DROP COLUMN status;
@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'suspended', 'banned', 'deleted'));
+ALTER TABLE accounts ADD COLUMN status_reason VARCHAR(32) NOT NULL DEFAULT '';
+
+UPDATE accounts SET status = 'deleted' WHERE deleted_at IS NOT NULL;
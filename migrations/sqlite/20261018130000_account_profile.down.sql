@@ -0,0 +1,5 @@
+ALTER TABLE accounts DROP COLUMN time_zone;
+ALTER TABLE accounts DROP COLUMN locale;
+ALTER TABLE accounts DROP COLUMN avatar_url;
+ALTER TABLE accounts DROP COLUMN bio;
+ALTER TABLE accounts DROP COLUMN display_name;
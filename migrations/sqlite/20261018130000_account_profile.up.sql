@@ -0,0 +1,5 @@
+ALTER TABLE accounts ADD COLUMN display_name VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN bio VARCHAR(280) NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN avatar_url VARCHAR(2048) NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE accounts ADD COLUMN avatar_thumbnail_url VARCHAR(2048) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS nick_history;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS nick_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id TEXT NOT NULL REFERENCES accounts (id),
+    nick VARCHAR(31) NOT NULL,
+    released_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS nick_history_nick_released_at_idx ON nick_history (nick, released_at DESC);
+CREATE INDEX IF NOT EXISTS nick_history_account_id_idx ON nick_history (account_id, released_at DESC);
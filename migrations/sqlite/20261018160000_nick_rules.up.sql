@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS nick_rules (
+    id TEXT PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('exact', 'prefix', 'pattern')),
+    value VARCHAR(255) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (kind, value)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS nick_history_nick_key_released_at_idx;
+CREATE INDEX IF NOT EXISTS nick_history_nick_released_at_idx ON nick_history (nick, released_at DESC);
+
+DROP INDEX IF EXISTS accounts_nick_key_key;
+ALTER TABLE accounts DROP COLUMN nick_key;
@@ -0,0 +1,9 @@
+-- Collisions between nicks differing only in case are reported by the
+-- migration precondition in Migrate. The case-sensitive accounts_nick_key
+-- constraint stays: SQLite cannot drop it without rebuilding the table, and
+-- it is implied by the new one.
+ALTER TABLE accounts ADD COLUMN nick_key VARCHAR(31) GENERATED ALWAYS AS (lower(nick)) VIRTUAL;
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_nick_key_key ON accounts (nick_key);
+
+DROP INDEX IF EXISTS nick_history_nick_released_at_idx;
+CREATE INDEX IF NOT EXISTS nick_history_nick_key_released_at_idx ON nick_history (lower(nick), released_at DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE accounts DROP COLUMN phone_flagged;
+ALTER TABLE accounts DROP COLUMN phone_type;
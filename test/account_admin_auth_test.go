@@ -0,0 +1,99 @@
+package test
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+// startAdminAuthClient serves over TCP so that requests carry a peer
+// address for trusted proxy checks.
+func startAdminAuthClient(t *testing.T, cfg grpcapi.AdminAuthConfig) accountv1.AccountServiceClient {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	acc := domain.Account{ID: uuid.New(), Nick: "@managed", Status: domain.StatusActive}
+	svc := accountsvc.New(grpcRepoStub{account: acc})
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcapi.NewAdminAuthorizer(cfg).UnaryInterceptor()))
+	accountv1.RegisterAccountServiceServer(s, grpcapi.NewServer(svc, slog.Default()))
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return accountv1.NewAccountServiceClient(conn)
+}
+
+func TestAdminMethodsRequireAdminCaller(t *testing.T) {
+	loopback := []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+	suspend := &accountv1.UpdateStatusRequest{Id: uuid.NewString(), Reason: accountv1.StatusReason_STATUS_REASON_SPAM}
+
+	tests := []struct {
+		name     string
+		cfg      grpcapi.AdminAuthConfig
+		callerID string
+		code     codes.Code
+		reason   string
+	}{
+		{name: "anonymous", cfg: grpcapi.AdminAuthConfig{Callers: []string{"ops"}, TrustedProxies: loopback},
+			code: codes.Unauthenticated, reason: grpcapi.ReasonUnauthenticated},
+		{name: "claimed without trusted proxy", cfg: grpcapi.AdminAuthConfig{Callers: []string{"ops"}},
+			callerID: "ops", code: codes.Unauthenticated, reason: grpcapi.ReasonUnauthenticated},
+		{name: "not an admin", cfg: grpcapi.AdminAuthConfig{Callers: []string{"ops"}, TrustedProxies: loopback},
+			callerID: "web", code: codes.PermissionDenied, reason: grpcapi.ReasonPermissionDenied},
+		{name: "no admins configured", cfg: grpcapi.AdminAuthConfig{TrustedProxies: loopback},
+			callerID: "ops", code: codes.PermissionDenied, reason: grpcapi.ReasonPermissionDenied},
+		{name: "admin", cfg: grpcapi.AdminAuthConfig{Callers: []string{"ops"}, TrustedProxies: loopback},
+			callerID: "ops", code: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := startAdminAuthClient(t, tt.cfg)
+			ctx := context.Background()
+			if tt.callerID != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, grpcapi.CallerIDHeader, tt.callerID)
+			}
+
+			_, err := client.SuspendAccount(ctx, suspend)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, err)
+			}
+			if tt.code != codes.OK {
+				if info, _ := statusDetails(t, err); info.GetReason() != tt.reason {
+					t.Fatalf("expected reason %s, got %s", tt.reason, info.GetReason())
+				}
+			}
+			if _, err = client.AddNickRule(ctx, &accountv1.AddNickRuleRequest{}); tt.code != codes.OK && status.Code(err) != tt.code {
+				t.Fatalf("expected AddNickRule to fail with %v, got %v", tt.code, err)
+			}
+
+			if _, err = client.GetAccount(ctx, &accountv1.GetAccountRequest{Id: uuid.NewString()}); err != nil {
+				t.Fatalf("expected other methods to pass, got %v", err)
+			}
+		})
+	}
+}
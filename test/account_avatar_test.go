@@ -0,0 +1,310 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/internal/adapters/blobstore"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+type memBlobStore struct {
+	blobs map[string][]byte
+}
+
+func newMemBlobStore() *memBlobStore {
+	return &memBlobStore{blobs: map[string][]byte{}}
+}
+
+func (m *memBlobStore) Put(_ context.Context, key, _ string, data []byte) (string, error) {
+	m.blobs[key] = data
+	return "https://blobs.example.com/" + key, nil
+}
+
+func (m *memBlobStore) Delete(_ context.Context, key string) error {
+	delete(m.blobs, key)
+	return nil
+}
+
+func (m *memBlobStore) Key(url string) (string, bool) {
+	return strings.CutPrefix(url, "https://blobs.example.com/")
+}
+
+func encodeTestImage(t *testing.T, width, height int, encode func(io.Writer, image.Image) error) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatalf("encode test image: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func avatarRepo(acc domain.Account) fakeRepo {
+	return fakeRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (domain.Account, error) {
+			return acc, nil
+		},
+		profileFn: func(_ context.Context, _ uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
+			updated := acc
+			updated.Profile.AvatarURL = *update.AvatarURL
+			updated.Profile.AvatarThumbnailURL = *update.AvatarThumbnailURL
+			return updated, nil
+		},
+	}
+}
+
+func TestUploadAvatarStoresImageAndThumbnail(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Status: domain.StatusActive}
+	blobs := newMemBlobStore()
+	svc := accountsvc.New(avatarRepo(acc), accountsvc.WithAvatarStore(blobs, accountsvc.AvatarConfig{ThumbnailSize: 32}))
+
+	upload := encodeTestImage(t, 200, 100, func(w io.Writer, img image.Image) error { return jpeg.Encode(w, img, nil) })
+	got, err := svc.UploadAvatar(context.Background(), acc.ID, bytes.NewReader(upload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(blobs.blobs) != 2 {
+		t.Fatalf("expected image and thumbnail to be stored, got %d blobs", len(blobs.blobs))
+	}
+	prefix := "https://blobs.example.com/avatars/" + acc.ID.String() + "/"
+	if !strings.HasPrefix(got.Profile.AvatarURL, prefix) || !strings.HasSuffix(got.Profile.AvatarURL, ".jpg") {
+		t.Fatalf("unexpected avatar url %q", got.Profile.AvatarURL)
+	}
+
+	thumbnailKey := strings.TrimPrefix(got.Profile.AvatarThumbnailURL, "https://blobs.example.com/")
+	thumbnail, format, err := image.DecodeConfig(bytes.NewReader(blobs.blobs[thumbnailKey]))
+	if err != nil {
+		t.Fatalf("decode thumbnail: %v", err)
+	}
+	if format != "jpeg" || thumbnail.Width != 32 || thumbnail.Height != 32 {
+		t.Fatalf("expected 32x32 jpeg thumbnail, got %dx%d %s", thumbnail.Width, thumbnail.Height, format)
+	}
+}
+
+func TestUploadAvatarDeletesReplacedUpload(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Status: domain.StatusActive}
+	acc.Profile.AvatarURL = "https://elsewhere.example.com/me.png"
+	blobs := newMemBlobStore()
+	repo := avatarRepo(acc)
+	repo.profileFn = func(_ context.Context, _ uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
+		acc.Profile.AvatarURL = *update.AvatarURL
+		acc.Profile.AvatarThumbnailURL = *update.AvatarThumbnailURL
+		return acc, nil
+	}
+	repo.getByIDFn = func(context.Context, uuid.UUID) (domain.Account, error) {
+		return acc, nil
+	}
+	svc := accountsvc.New(repo, accountsvc.WithAvatarStore(blobs, accountsvc.AvatarConfig{}))
+	upload := encodeTestImage(t, 20, 20, png.Encode)
+
+	first, err := svc.UploadAvatar(context.Background(), acc.ID, bytes.NewReader(upload))
+	if err != nil {
+		t.Fatalf("first upload failed: %v", err)
+	}
+	second, err := svc.UploadAvatar(context.Background(), acc.ID, bytes.NewReader(upload))
+	if err != nil {
+		t.Fatalf("second upload failed: %v", err)
+	}
+
+	if len(blobs.blobs) != 2 {
+		t.Fatalf("expected only the latest image and thumbnail to be kept, got %d blobs", len(blobs.blobs))
+	}
+	for _, u := range []string{first.Profile.AvatarURL, first.Profile.AvatarThumbnailURL} {
+		if key, _ := blobs.Key(u); blobs.blobs[key] != nil {
+			t.Fatalf("expected replaced blob %q to be deleted", key)
+		}
+	}
+	for _, u := range []string{second.Profile.AvatarURL, second.Profile.AvatarThumbnailURL} {
+		if key, _ := blobs.Key(u); blobs.blobs[key] == nil {
+			t.Fatalf("expected current blob %q to be kept", key)
+		}
+	}
+}
+
+func TestUploadAvatarRejectsInsecureBlobURLs(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Status: domain.StatusActive}
+	store, err := blobstore.NewLocal(t.TempDir(), "http://localhost:9091/avatars/")
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	svc := accountsvc.New(avatarRepo(acc), accountsvc.WithAvatarStore(store, accountsvc.AvatarConfig{}))
+
+	if _, err = svc.UploadAvatar(context.Background(), acc.ID, bytes.NewReader(encodeTestImage(t, 20, 20, png.Encode))); err == nil {
+		t.Fatal("expected an http blob url to be rejected")
+	}
+}
+
+func TestUploadAvatarRejectsInvalidUploads(t *testing.T) {
+	active := domain.Account{ID: uuid.New(), Status: domain.StatusActive}
+	pngImage := func(width, height int) []byte {
+		return encodeTestImage(t, width, height, png.Encode)
+	}
+
+	tests := []struct {
+		name    string
+		acc     domain.Account
+		upload  []byte
+		wantErr error
+	}{
+		{"too large", active, bytes.Repeat([]byte{0}, 2048), domain.ErrAvatarTooLarge},
+		{"not an image", active, []byte("definitely not an image"), domain.ErrInvalidAvatar},
+		{"too many pixels", active, pngImage(80, 10), domain.ErrInvalidAvatar},
+		{"suspended account", domain.Account{ID: active.ID, Status: domain.StatusSuspended}, pngImage(10, 10), domain.ErrAccountNotActive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blobs := newMemBlobStore()
+			svc := accountsvc.New(avatarRepo(tt.acc), accountsvc.WithAvatarStore(blobs, accountsvc.AvatarConfig{MaxBytes: 1024, MaxDimension: 64}))
+
+			_, err := svc.UploadAvatar(context.Background(), tt.acc.ID, bytes.NewReader(tt.upload))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if len(blobs.blobs) != 0 {
+				t.Fatalf("expected nothing to be stored, got %d blobs", len(blobs.blobs))
+			}
+		})
+	}
+}
+
+func TestUploadAvatarGRPC(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	acc, err := repo.Create(context.Background(), uuid.New(), "@avatar_owner", domain.Phone{Number: "+15550000501"}, 0)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	store, err := blobstore.NewLocal(t.TempDir(), "https://cdn.example.com/avatars")
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	client := startGRPCClient(t, repo, accountsvc.WithAvatarStore(store, accountsvc.AvatarConfig{}))
+
+	stream, err := client.UploadAvatar(context.Background())
+	if err != nil {
+		t.Fatalf("UploadAvatar failed: %v", err)
+	}
+	if err = stream.Send(&accountv1.UploadAvatarRequest{Payload: &accountv1.UploadAvatarRequest_Id{Id: acc.ID.String()}}); err != nil {
+		t.Fatalf("send id: %v", err)
+	}
+	upload := encodeTestImage(t, 300, 300, png.Encode)
+	for chunk := range slices.Chunk(upload, 4096) {
+		if err = stream.Send(&accountv1.UploadAvatarRequest{Payload: &accountv1.UploadAvatarRequest_Chunk{Chunk: chunk}}); err != nil {
+			t.Fatalf("send chunk: %v", err)
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("CloseAndRecv failed: %v", err)
+	}
+
+	profile := resp.GetAccount().GetProfile()
+	if !strings.HasPrefix(profile.GetAvatarUrl(), "https://cdn.example.com/avatars/avatars/"+acc.ID.String()+"/") {
+		t.Fatalf("unexpected avatar url %q", profile.GetAvatarUrl())
+	}
+
+	srv := httptest.NewServer(http.StripPrefix("/cdn", store.Handler()))
+	defer srv.Close()
+	thumbnailPath := strings.TrimPrefix(profile.GetAvatarThumbnailUrl(), "https://cdn.example.com")
+	res, err := http.Get(srv.URL + "/cdn" + thumbnailPath)
+	if err != nil {
+		t.Fatalf("fetch thumbnail: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "image/png" {
+		t.Fatalf("expected thumbnail to be served as image/png, got %d %q", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+}
+
+func TestUploadAvatarGRPCDisabled(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{})
+
+	stream, err := client.UploadAvatar(context.Background())
+	if err != nil {
+		t.Fatalf("UploadAvatar failed: %v", err)
+	}
+	if err = stream.Send(&accountv1.UploadAvatarRequest{Payload: &accountv1.UploadAvatarRequest_Id{Id: uuid.New().String()}}); err != nil {
+		t.Fatalf("send id: %v", err)
+	}
+
+	_, err = stream.CloseAndRecv()
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented, got %v", status.Code(err))
+	}
+}
+
+func TestLocalBlobStoreRejectsEscapingKeys(t *testing.T) {
+	store, err := blobstore.NewLocal(t.TempDir(), "https://cdn.example.com/")
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+
+	for _, key := range []string{"../outside.png", "/absolute.png", "a/../../b.png", ""} {
+		if _, err = store.Put(context.Background(), key, "image/png", []byte("x")); err == nil {
+			t.Fatalf("expected key %q to be rejected", key)
+		}
+	}
+}
+
+func TestLocalBlobStoreHandlerHidesDirectories(t *testing.T) {
+	store, err := blobstore.NewLocal(t.TempDir(), "https://cdn.example.com/avatars/")
+	if err != nil {
+		t.Fatalf("NewLocal failed: %v", err)
+	}
+	u, err := store.Put(context.Background(), "avatars/owner/a.png", "image/png", []byte("x"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if key, ok := store.Key(u); !ok || key != "avatars/owner/a.png" {
+		t.Fatalf("expected the key of %q, got %q, %v", u, key, ok)
+	}
+	if _, ok := store.Key("https://other.example.com/avatars/avatars/owner/a.png"); ok {
+		t.Fatal("expected a foreign url to have no key")
+	}
+
+	srv := httptest.NewServer(store.Handler())
+	defer srv.Close()
+
+	for path, want := range map[string]int{
+		"/avatars/avatars/owner/a.png": http.StatusOK,
+		"/avatars/":                    http.StatusNotFound,
+		"/avatars/avatars/":            http.StatusNotFound,
+		"/avatars/avatars/owner/":      http.StatusNotFound,
+	} {
+		res, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != want {
+			t.Fatalf("GET %s: expected %d, got %d", path, want, res.StatusCode)
+		}
+	}
+}
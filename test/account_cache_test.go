@@ -0,0 +1,233 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/adapters/cache"
+	"github.com/kvetinski/account/internal/domain"
+)
+
+type recordingInvalidator struct {
+	published []uuid.UUID
+}
+
+func (r *recordingInvalidator) Publish(_ context.Context, id uuid.UUID) error {
+	r.published = append(r.published, id)
+	return nil
+}
+
+func countingGetRepo(calls *int, acc domain.Account, err error) fakeRepo {
+	return fakeRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (domain.Account, error) {
+			*calls++
+			return acc, err
+		},
+		updateNickFn: func(_ context.Context, id uuid.UUID, nick string) (domain.Account, error) {
+			return domain.Account{ID: id, Nick: nick}, nil
+		},
+		deleteFn: func(_ context.Context, _ uuid.UUID) error {
+			return nil
+		},
+	}
+}
+
+func TestCacheServesRepeatedReadsFromMemory(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@cached"}
+	calls := 0
+	repo := cache.New(countingGetRepo(&calls, acc, nil), cache.Config{Size: 10, TTL: time.Minute}, nil, nil)
+
+	for range 3 {
+		got, err := repo.GetByID(context.Background(), acc.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Nick != acc.Nick {
+			t.Fatalf("expected nick %s, got %s", acc.Nick, got.Nick)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected 1 repository call, got %d", calls)
+	}
+}
+
+func TestCacheNegativeCaching(t *testing.T) {
+	calls := 0
+	repo := cache.New(countingGetRepo(&calls, domain.Account{}, domain.ErrAccountNotFound), cache.Config{Size: 10, TTL: time.Minute, NegativeTTL: time.Minute}, nil, nil)
+
+	id := uuid.New()
+	for range 2 {
+		_, err := repo.GetByID(context.Background(), id)
+		if !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Fatalf("expected ErrAccountNotFound, got %v", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected not-found to be cached, got %d repository calls", calls)
+	}
+}
+
+func TestCacheEntriesExpire(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@short_lived"}
+	calls := 0
+	repo := cache.New(countingGetRepo(&calls, acc, nil), cache.Config{Size: 10, TTL: 10 * time.Millisecond}, nil, nil)
+
+	if _, err := repo.GetByID(context.Background(), acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := repo.GetByID(context.Background(), acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected expired entry to be refetched, got %d repository calls", calls)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	calls := 0
+	repo := cache.New(countingGetRepo(&calls, domain.Account{Nick: "@any"}, nil), cache.Config{Size: 1, TTL: time.Minute}, nil, nil)
+
+	first, second := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{first, second, first} {
+		if _, err := repo.GetByID(context.Background(), id); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if calls != 3 {
+		t.Fatalf("expected evicted entry to be refetched, got %d repository calls", calls)
+	}
+	if repo.Len() != 1 {
+		t.Fatalf("expected cache size 1, got %d", repo.Len())
+	}
+}
+
+func TestCacheMutationsInvalidateAndPublish(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@before"}
+	calls := 0
+	invalidator := &recordingInvalidator{}
+	repo := cache.New(countingGetRepo(&calls, acc, nil), cache.Config{Size: 10, TTL: time.Minute}, invalidator, nil)
+	ctx := context.Background()
+
+	if _, err := repo.GetByID(ctx, acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.UpdateNick(ctx, acc.ID, "@after", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.Delete(ctx, acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("expected each mutation to evict the entry, got %d repository calls", calls)
+	}
+	if len(invalidator.published) != 2 || invalidator.published[0] != acc.ID || invalidator.published[1] != acc.ID {
+		t.Fatalf("expected two published invalidations for %s, got %v", acc.ID, invalidator.published)
+	}
+}
+
+func TestCacheRemoteInvalidation(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@remote"}
+	calls := 0
+	repo := cache.New(countingGetRepo(&calls, acc, nil), cache.Config{Size: 10, TTL: time.Minute}, nil, nil)
+	ctx := context.Background()
+
+	if _, err := repo.GetByID(ctx, acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.Invalidate(acc.ID)
+	if _, err := repo.GetByID(ctx, acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.Purge()
+	if _, err := repo.GetByID(ctx, acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("expected invalidate and purge to evict the entry, got %d repository calls", calls)
+	}
+}
+
+func TestCacheBypassedWithConsistencyToken(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@fresh"}
+	calls := 0
+	repo := cache.New(countingGetRepo(&calls, acc, nil), cache.Config{Size: 10, TTL: time.Minute}, nil, nil)
+
+	if _, err := repo.GetByID(context.Background(), acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.GetByID(domain.WithConsistencyToken(context.Background(), "token"), acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected read with consistency token to skip the cache, got %d repository calls", calls)
+	}
+}
+
+func TestCacheFillKeptWhenAnotherAccountIsInvalidated(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@filling"}
+	other := uuid.New()
+	calls := 0
+	var repo *cache.Repository
+	repo = cache.New(fakeRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (domain.Account, error) {
+			calls++
+			if calls == 1 {
+				repo.Invalidate(other)
+			}
+			return acc, nil
+		},
+	}, cache.Config{Size: 10, TTL: time.Minute}, nil, nil)
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := repo.GetByID(ctx, acc.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected the fill to survive an invalidation of another account, got %d repository calls", calls)
+	}
+}
+
+func TestCacheFillRacingInvalidationIsDropped(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@stale"}
+	calls := 0
+	var repo *cache.Repository
+	repo = cache.New(fakeRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (domain.Account, error) {
+			calls++
+			if calls == 1 {
+				repo.Invalidate(id)
+			}
+			return acc, nil
+		},
+	}, cache.Config{Size: 10, TTL: time.Minute}, nil, nil)
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := repo.GetByID(ctx, acc.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected the racing fill to be dropped and the next one kept, got %d repository calls", calls)
+	}
+}
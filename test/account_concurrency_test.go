@@ -0,0 +1,195 @@
+package test
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+// runConcurrencyWindows fills the limiter up to its limit and completes the
+// requests with latency, rounds times.
+func runConcurrencyWindows(t *testing.T, l *grpcapi.ConcurrencyLimiter, rounds int, latency time.Duration) {
+	t.Helper()
+
+	for range rounds {
+		n := l.Limit()
+		for i := range n {
+			if !l.Acquire() {
+				t.Fatalf("expected request %d of %d to be admitted", i+1, n)
+			}
+		}
+		if l.Acquire() {
+			t.Fatalf("expected request over the limit of %d to be shed", n)
+		}
+		for range n {
+			l.Release(latency)
+		}
+	}
+}
+
+func TestConcurrencyLimiterAdaptsToLatency(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	l := grpcapi.NewConcurrencyLimiter(grpcapi.ConcurrencyLimitConfig{
+		InitialLimit: 10,
+		MinLimit:     2,
+		MaxLimit:     40,
+		WindowSize:   5,
+	}, telemetry.NewMetrics(registry))
+
+	runConcurrencyWindows(t, l, 20, 10*time.Millisecond)
+	grown := l.Limit()
+	if grown <= 10 {
+		t.Fatalf("expected the limit to grow at steady latency, got %d", grown)
+	}
+	if grown > 40 {
+		t.Fatalf("expected the limit to stay within the maximum, got %d", grown)
+	}
+
+	runConcurrencyWindows(t, l, 10, 100*time.Millisecond)
+	shrunk := l.Limit()
+	if shrunk >= grown {
+		t.Fatalf("expected the limit to shrink when latency rises, got %d after %d", shrunk, grown)
+	}
+
+	if got := gaugeValue(t, registry, "account_grpc_concurrency_limit"); int(got) != shrunk {
+		t.Fatalf("expected the gauge to export limit %d, got %v", shrunk, got)
+	}
+}
+
+func gaugeValue(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() == name && len(f.GetMetric()) == 1 {
+			return f.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return 0
+}
+
+func TestConcurrencyLimiterShedsWithUnavailable(t *testing.T) {
+	l := grpcapi.NewConcurrencyLimiter(grpcapi.ConcurrencyLimitConfig{InitialLimit: 1, MinLimit: 1, MaxLimit: 1}, nil)
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	svc := accountsvc.New(fakeRepo{
+		getByIDFn: func(context.Context, uuid.UUID) (domain.Account, error) {
+			close(entered)
+			<-release
+			return domain.Account{ID: uuid.New(), Nick: "@slow"}, nil
+		},
+	})
+	client := startGRPCServer(t, svc, grpc.ChainUnaryInterceptor(l.UnaryInterceptor()))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetAccount(context.Background(), &accountv1.GetAccountRequest{Id: uuid.New().String()})
+		done <- err
+	}()
+	<-entered
+
+	_, err := client.CheckNick(context.Background(), &accountv1.CheckNickRequest{Nick: "@x"})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable while at the limit, got %v", err)
+	}
+
+	close(release)
+	if err = <-done; err != nil {
+		t.Fatalf("GetAccount failed: %v", err)
+	}
+	if _, err = client.CheckNick(context.Background(), &accountv1.CheckNickRequest{Nick: "@x"}); err != nil {
+		t.Fatalf("expected a request to be admitted after release, got %v", err)
+	}
+}
+
+// startUploadLimitedClient serves an avatar-less service over bufconn with
+// the upload limits and the concurrency limiter l.
+func startUploadLimitedClient(t *testing.T, limits grpcapi.UploadLimits, l *grpcapi.ConcurrencyLimiter) accountv1.AccountServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(bufSize)
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(l.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(l.StreamInterceptor()))
+	accountv1.RegisterAccountServiceServer(s, grpcapi.NewServer(accountsvc.New(grpcRepoStub{}), slog.Default(), grpcapi.WithUploadLimits(limits)))
+	go func() {
+		_ = s.Serve(listener)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial grpc: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return accountv1.NewAccountServiceClient(conn)
+}
+
+func TestConcurrencyLimiterExemptsUploads(t *testing.T) {
+	l := grpcapi.NewConcurrencyLimiter(grpcapi.ConcurrencyLimitConfig{InitialLimit: 1, MinLimit: 1, MaxLimit: 1}, nil)
+	client := startUploadLimitedClient(t, grpcapi.UploadLimits{MaxConcurrent: 1, Timeout: time.Minute}, l)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// An upload that never sends anything holds an upload slot...
+	if _, err := client.UploadAvatar(ctx); err != nil {
+		t.Fatalf("UploadAvatar failed: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		second, err := client.UploadAvatar(ctx)
+		if err != nil {
+			t.Fatalf("UploadAvatar failed: %v", err)
+		}
+		if _, err = second.CloseAndRecv(); status.Code(err) == codes.Unavailable {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a second upload to fail with Unavailable, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// ...but no concurrency slot, so other requests still get through.
+	if _, err := client.CheckNick(context.Background(), &accountv1.CheckNickRequest{Nick: "@x"}); err != nil {
+		t.Fatalf("expected unary requests to be admitted during an upload, got %v", err)
+	}
+}
+
+func TestUploadAvatarTimeout(t *testing.T) {
+	l := grpcapi.NewConcurrencyLimiter(grpcapi.ConcurrencyLimitConfig{InitialLimit: 1, MinLimit: 1, MaxLimit: 1}, nil)
+	client := startUploadLimitedClient(t, grpcapi.UploadLimits{Timeout: 50 * time.Millisecond}, l)
+
+	stream, err := client.UploadAvatar(context.Background())
+	if err != nil {
+		t.Fatalf("UploadAvatar failed: %v", err)
+	}
+	// Wait for the reply without closing the stream, like a stalled client.
+	if err = stream.RecvMsg(new(accountv1.AccountResponse)); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected a stalled upload to fail with DeadlineExceeded, got %v", err)
+	}
+}
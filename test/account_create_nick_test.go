@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+func TestCreateWithRequestedNick(t *testing.T) {
+	taken := map[string]bool{"@taken": true}
+	var created []string
+	svc := accountsvc.New(fakeRepo{
+		createFn: func(_ context.Context, id uuid.UUID, nick, phone string) (domain.Account, error) {
+			created = append(created, nick)
+			if taken[nick] {
+				return domain.Account{}, domain.ErrNickAlreadyExists
+			}
+			return domain.Account{ID: id, Nick: nick, Phone: phone}, nil
+		},
+	}, accountsvc.WithNickFilter(testWordlistFilter()))
+	ctx := context.Background()
+
+	acc, err := svc.Create(ctx, domain.NewAccount{Phone: "+15551234567", Nick: " @Chosen "})
+	if err != nil || acc.Nick != "@Chosen" {
+		t.Fatalf("expected requested nick, got %+v, %v", acc, err)
+	}
+
+	if _, err = svc.Create(ctx, domain.NewAccount{Phone: "+15551234567", Nick: "@taken"}); !errors.Is(err, domain.ErrNickAlreadyExists) {
+		t.Fatalf("expected ErrNickAlreadyExists without fallback, got %v", err)
+	}
+	if _, err = svc.Create(ctx, domain.NewAccount{Phone: "+15551234567", Nick: "@the_admin"}); !errors.Is(err, domain.ErrNickNotAllowed) {
+		t.Fatalf("expected ErrNickNotAllowed without fallback, got %v", err)
+	}
+	if _, err = svc.Create(ctx, domain.NewAccount{Phone: "+15551234567", Nick: "no_at", GenerateNickIfUnavailable: true}); !errors.Is(err, domain.ErrInvalidNick) {
+		t.Fatalf("expected ErrInvalidNick even with fallback, got %v", err)
+	}
+
+	for _, nick := range []string{"@taken", "@the_admin"} {
+		created = nil
+		acc, err = svc.Create(ctx, domain.NewAccount{Phone: "+15551234567", Nick: nick, GenerateNickIfUnavailable: true})
+		if err != nil || acc.Nick == nick || acc.Nick == "" {
+			t.Fatalf("expected a generated nick instead of %s, got %+v, %v", nick, acc, err)
+		}
+		if created[len(created)-1] != acc.Nick {
+			t.Fatalf("expected the generated nick to be stored, got %v", created)
+		}
+	}
+}
+
+func TestCreateWithRequestedNickDoesNotFallBackOnOtherErrors(t *testing.T) {
+	calls := 0
+	svc := accountsvc.New(fakeRepo{
+		createFn: func(context.Context, uuid.UUID, string, string) (domain.Account, error) {
+			calls++
+			return domain.Account{}, domain.ErrPhoneAlreadyExists
+		},
+	})
+
+	_, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+15551234567", Nick: "@chosen", GenerateNickIfUnavailable: true})
+	if !errors.Is(err, domain.ErrPhoneAlreadyExists) || calls != 1 {
+		t.Fatalf("expected ErrPhoneAlreadyExists after one call, got %v after %d", err, calls)
+	}
+}
+
+// Concurrent signups for one nick must leave exactly one account: the nick
+// is claimed in the transaction that inserts the account.
+func TestCreateWithRequestedNickIsAtomic(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	svc := accountsvc.New(repo)
+	ctx := context.Background()
+
+	const signups = 8
+	errs := make([]error, signups)
+	var wg sync.WaitGroup
+	for i := range signups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = svc.Create(ctx, domain.NewAccount{Phone: fmt.Sprintf("+1555000%04d", i), Nick: "@wanted"})
+		}()
+	}
+	wg.Wait()
+
+	won := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			won++
+		case errors.Is(err, domain.ErrNickAlreadyExists):
+			// The losing phone must not have been left with an account.
+			if _, err = svc.Create(ctx, domain.NewAccount{Phone: fmt.Sprintf("+1555000%04d", i)}); err != nil {
+				t.Fatalf("expected phone of losing signup %d to be free, got %v", i, err)
+			}
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if won != 1 {
+		t.Fatalf("expected exactly one signup to win @wanted, got %d", won)
+	}
+}
+
+func TestCreateAccountGRPCWithNick(t *testing.T) {
+	ctx := context.Background()
+	client := startGRPCClient(t, newSQLiteRepo(t))
+
+	resp, err := client.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: "+15551230001", Nick: "@grpc_nick"})
+	if err != nil || resp.GetAccount().GetNick() != "@grpc_nick" {
+		t.Fatalf("expected @grpc_nick, got %v, %v", resp, err)
+	}
+
+	_, err = client.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: "+15551230002", Nick: "@GRPC_NICK"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", status.Code(err))
+	}
+
+	resp, err = client.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: "+15551230002", Nick: "@GRPC_NICK", GenerateNickIfUnavailable: true})
+	if err != nil || resp.GetAccount().GetNick() == "" || resp.GetAccount().GetNick() == "@GRPC_NICK" {
+		t.Fatalf("expected a generated nick, got %v, %v", resp, err)
+	}
+
+	_, err = client.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: "+15551230003", Nick: "bad nick"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", status.Code(err))
+	}
+}
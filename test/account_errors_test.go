@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+// statusDetails returns the ErrorInfo and the BadRequest detail of err.
+func statusDetails(t *testing.T, err error) (*errdetails.ErrorInfo, *errdetails.BadRequest) {
+	t.Helper()
+
+	var info *errdetails.ErrorInfo
+	var badRequest *errdetails.BadRequest
+	for _, d := range status.Convert(err).Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			badRequest = d
+		}
+	}
+	if info == nil {
+		t.Fatalf("expected ErrorInfo detail in %v", err)
+	}
+
+	return info, badRequest
+}
+
+func TestCreateAccountGRPCFieldViolation(t *testing.T) {
+	client := startGRPCClient(t, newSQLiteRepo(t))
+
+	_, err := client.CreateAccount(context.Background(), &accountv1.CreateAccountRequest{Phone: "+15550012001", Nick: "@x"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+
+	info, badRequest := statusDetails(t, err)
+	if info.GetReason() != grpcapi.ReasonInvalidNick || info.GetDomain() != grpcapi.ErrorDomain {
+		t.Fatalf("unexpected ErrorInfo: %v", info)
+	}
+	violations := badRequest.GetFieldViolations()
+	if len(violations) != 1 || violations[0].GetField() != "nick" || violations[0].GetReason() != grpcapi.ReasonInvalidNick || violations[0].GetDescription() == "" {
+		t.Fatalf("unexpected field violations: %v", violations)
+	}
+
+	if err = grpcapi.DomainError(err); !errors.Is(err, domain.ErrInvalidNick) {
+		t.Fatalf("expected DomainError to return ErrInvalidNick, got %v", err)
+	}
+}
+
+func TestUpdateProfileGRPCViolationNamesLimit(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{})
+
+	name := strings.Repeat("n", accountsvc.MaxDisplayNameLength+1)
+	_, err := client.UpdateProfile(context.Background(), &accountv1.UpdateProfileRequest{Id: uuid.New().String(), DisplayName: &name})
+	_, badRequest := statusDetails(t, err)
+	violations := badRequest.GetFieldViolations()
+	if len(violations) != 1 || violations[0].GetField() != "display_name" ||
+		!strings.Contains(violations[0].GetDescription(), fmt.Sprintf("at most %d characters", accountsvc.MaxDisplayNameLength)) {
+		t.Fatalf("unexpected field violations: %v", violations)
+	}
+}
+
+func TestUpdateNickGRPCFilteredNickViolation(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{}, accountsvc.WithNickFilter(testWordlistFilter()))
+
+	_, err := client.UpdateNick(context.Background(), &accountv1.UpdateNickRequest{Id: uuid.New().String(), Nick: "@f_u_c_k"})
+	_, badRequest := statusDetails(t, err)
+	violations := badRequest.GetFieldViolations()
+	if len(violations) != 1 || violations[0].GetField() != "nick" || violations[0].GetDescription() != "must not contain words of the profanity wordlist" {
+		t.Fatalf("unexpected field violations: %v", violations)
+	}
+
+	var notAllowed *domain.NickNotAllowedError
+	if err = grpcapi.DomainError(err); !errors.As(err, &notAllowed) || notAllowed.Category != "profanity" {
+		t.Fatalf("expected DomainError to rebuild NickNotAllowedError, got %#v", err)
+	}
+	if !errors.Is(err, domain.ErrNickNotAllowed) {
+		t.Fatalf("expected %v to match ErrNickNotAllowed", err)
+	}
+}
+
+func TestGetAccountGRPCNotFoundDetails(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{err: domain.ErrAccountNotFound})
+
+	_, err := client.GetAccount(context.Background(), &accountv1.GetAccountRequest{Id: uuid.New().String()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	info, badRequest := statusDetails(t, err)
+	if info.GetReason() != grpcapi.ReasonAccountNotFound {
+		t.Fatalf("unexpected ErrorInfo: %v", info)
+	}
+	if badRequest != nil {
+		t.Fatalf("expected no field violations, got %v", badRequest)
+	}
+	if err = grpcapi.DomainError(err); err != domain.ErrAccountNotFound {
+		t.Fatalf("expected DomainError to return ErrAccountNotFound, got %v", err)
+	}
+}
+
+func TestDomainErrorRateLimited(t *testing.T) {
+	client := startRateLimitedClient(t, grpcapi.RateLimitConfig{
+		Methods: map[string]grpcapi.Rate{grpcapi.DefaultRateLimitMethod: {PerSecond: 0.001, Burst: 1}},
+	}, nil)
+
+	if _, err := client.CheckNick(context.Background(), &accountv1.CheckNickRequest{Nick: "@free"}); err != nil {
+		t.Fatalf("CheckNick failed: %v", err)
+	}
+	_, err := client.CheckNick(context.Background(), &accountv1.CheckNickRequest{Nick: "@free"})
+
+	var limited *domain.RateLimitedError
+	if err = grpcapi.DomainError(err); !errors.As(err, &limited) {
+		t.Fatalf("expected DomainError to rebuild RateLimitedError, got %v", err)
+	}
+	if limited.Scope != grpcapi.RateLimitScopeMethod || limited.RetryAfter <= 0 {
+		t.Fatalf("unexpected rate limit error: %+v", limited)
+	}
+}
+
+func TestDomainErrorKeepsOtherErrors(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{err: errors.New("connection refused")})
+
+	_, err := client.GetAccount(context.Background(), &accountv1.GetAccountRequest{Id: uuid.New().String()})
+	if got := grpcapi.DomainError(err); got != err || status.Code(got) != codes.Internal {
+		t.Fatalf("expected the Internal status to be returned as is, got %v", got)
+	}
+
+	plain := errors.New("not a status")
+	if got := grpcapi.DomainError(plain); got != plain {
+		t.Fatalf("expected a non-status error to be returned as is, got %v", got)
+	}
+	if grpcapi.DomainError(nil) != nil {
+		t.Fatal("expected nil to stay nil")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,8 +12,10 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/kvetinski/account/internal/adapters/grpcapi"
 	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
@@ -23,45 +26,102 @@ import (
 const bufSize = 1024 * 1024
 
 type grpcRepoStub struct {
-	account domain.Account
-	err     error
+	account   domain.Account
+	err       error
+	token     string
+	seenToken *string
 }
 
-func (s grpcRepoStub) Create(_ context.Context, _ uuid.UUID, _, _ string) (domain.Account, error) {
+func (s grpcRepoStub) Create(_ context.Context, _ uuid.UUID, _ string, _ domain.Phone, _ time.Duration) (domain.Account, error) {
 	panic("unexpected call")
 }
 
-func (s grpcRepoStub) GetByID(_ context.Context, _ uuid.UUID) (domain.Account, error) {
+func (s grpcRepoStub) GetByID(ctx context.Context, _ uuid.UUID) (domain.Account, error) {
+	if s.seenToken != nil {
+		*s.seenToken = domain.ConsistencyTokenFrom(ctx)
+	}
 	if s.err != nil {
 		return domain.Account{}, s.err
 	}
 	return s.account, nil
 }
 
-func (s grpcRepoStub) UpdateNick(_ context.Context, _ uuid.UUID, _ string) (domain.Account, error) {
+func (s grpcRepoStub) GetByNick(_ context.Context, _ string, _ time.Duration) (domain.Account, error) {
+	if s.err != nil {
+		return domain.Account{}, s.err
+	}
+	return s.account, nil
+}
+
+func (s grpcRepoStub) ListNickHistory(_ context.Context, _ uuid.UUID) ([]domain.NickChange, error) {
 	panic("unexpected call")
 }
 
+func (s grpcRepoStub) CheckNicks(_ context.Context, _ uuid.UUID, nicks []string, _ time.Duration) (map[string]domain.NickCheckReason, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	taken := map[string]domain.NickCheckReason{}
+	for _, nick := range nicks {
+		if strings.EqualFold(nick, s.account.Nick) {
+			taken[strings.ToLower(nick)] = domain.NickCheckTaken
+		}
+	}
+	return taken, nil
+}
+
+func (s grpcRepoStub) UpdateNick(_ context.Context, _ uuid.UUID, nick string, _ time.Duration) (domain.Account, error) {
+	if s.err != nil {
+		return domain.Account{}, s.err
+	}
+	acc := s.account
+	acc.Nick = nick
+	return acc, nil
+}
+
+func (s grpcRepoStub) UpdateProfile(_ context.Context, _ uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
+	if s.err != nil {
+		return domain.Account{}, s.err
+	}
+	acc := s.account
+	if update.DisplayName != nil {
+		acc.Profile.DisplayName = *update.DisplayName
+	}
+	if update.Locale != nil {
+		acc.Profile.Locale = *update.Locale
+	}
+	return acc, nil
+}
+
+func (s grpcRepoStub) UpdateStatus(_ context.Context, _ uuid.UUID, to domain.Status, reason domain.StatusReason, _ ...domain.Status) (domain.Account, error) {
+	if s.err != nil {
+		return domain.Account{}, s.err
+	}
+	acc := s.account
+	acc.Status, acc.StatusReason = to, reason
+	return acc, nil
+}
+
 func (s grpcRepoStub) Delete(_ context.Context, _ uuid.UUID) error {
 	panic("unexpected call")
 }
 
-func startGRPCClient(t *testing.T, repo grpcRepoStub) accountv1.AccountServiceClient {
+func (s grpcRepoStub) ConsistencyToken(_ context.Context) (string, error) {
+	return s.token, nil
+}
+
+func startGRPCClient(t *testing.T, repo accountsvc.Repository, opts ...accountsvc.Option) accountv1.AccountServiceClient {
 	t.Helper()
 
-	listener := bufconn.Listen(bufSize)
-	s := grpc.NewServer()
-	svc := accountsvc.New(repo)
-	accountv1.RegisterAccountServiceServer(s, grpcapi.NewServer(svc, slog.Default()))
+	return startGRPCServer(t, accountsvc.New(repo, opts...))
+}
 
-	go func() {
-		_ = s.Serve(listener)
-	}()
+// startGRPCServer serves svc over bufconn with serverOpts, e.g.
+// interceptors, and returns a client for it.
+func startGRPCServer(t *testing.T, svc *accountsvc.Service, serverOpts ...grpc.ServerOption) accountv1.AccountServiceClient {
+	t.Helper()
 
-	t.Cleanup(func() {
-		s.Stop()
-		_ = listener.Close()
-	})
+	listener := serveGRPC(t, svc, serverOpts...)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	t.Cleanup(cancel)
@@ -82,6 +142,27 @@ func startGRPCClient(t *testing.T, repo grpcRepoStub) accountv1.AccountServiceCl
 	return accountv1.NewAccountServiceClient(conn)
 }
 
+// serveGRPC serves svc over bufconn with serverOpts and returns the listener
+// to dial.
+func serveGRPC(t *testing.T, svc *accountsvc.Service, serverOpts ...grpc.ServerOption) *bufconn.Listener {
+	t.Helper()
+
+	listener := bufconn.Listen(bufSize)
+	s := grpc.NewServer(serverOpts...)
+	accountv1.RegisterAccountServiceServer(s, grpcapi.NewServer(svc, slog.Default()))
+
+	go func() {
+		_ = s.Serve(listener)
+	}()
+
+	t.Cleanup(func() {
+		s.Stop()
+		_ = listener.Close()
+	})
+
+	return listener
+}
+
 func TestGetAccountGRPCSuccess(t *testing.T) {
 	now := time.Now().UTC().Truncate(time.Second)
 	acc := domain.Account{ID: uuid.New(), Nick: "@john", Phone: "+15551234567", CreatedAt: now, UpdatedAt: now}
@@ -120,3 +201,162 @@ func TestGetAccountGRPCInvalidID(t *testing.T) {
 		t.Fatalf("expected InvalidArgument, got %v", status.Code(err))
 	}
 }
+
+func TestUpdateNickGRPCReturnsConsistencyToken(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@john", Phone: "+15551234567"}
+	client := startGRPCClient(t, grpcRepoStub{account: acc, token: "token-1"})
+
+	var header metadata.MD
+	resp, err := client.UpdateNick(context.Background(), &accountv1.UpdateNickRequest{Id: acc.ID.String(), Nick: "@johnny"}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.GetConsistencyToken() != "token-1" {
+		t.Fatalf("expected consistency token token-1, got %q", resp.GetConsistencyToken())
+	}
+	if got := header.Get(grpcapi.ConsistencyTokenHeader); len(got) != 1 || got[0] != "token-1" {
+		t.Fatalf("expected consistency token header token-1, got %v", got)
+	}
+}
+
+func TestGetAccountGRPCPassesConsistencyToken(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@john", Phone: "+15551234567"}
+
+	var seen string
+	client := startGRPCClient(t, grpcRepoStub{account: acc, seenToken: &seen})
+
+	if _, err := client.GetAccount(context.Background(), &accountv1.GetAccountRequest{Id: acc.ID.String(), ConsistencyToken: "from-field"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen != "from-field" {
+		t.Fatalf("expected repository to see token from-field, got %q", seen)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), grpcapi.ConsistencyTokenHeader, "from-header")
+	if _, err := client.GetAccount(ctx, &accountv1.GetAccountRequest{Id: acc.ID.String()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen != "from-header" {
+		t.Fatalf("expected repository to see token from-header, got %q", seen)
+	}
+}
+
+func TestGetAccountGRPCInvalidConsistencyToken(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{err: domain.ErrInvalidConsistencyToken})
+
+	_, err := client.GetAccount(context.Background(), &accountv1.GetAccountRequest{Id: uuid.New().String(), ConsistencyToken: "bogus"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", status.Code(err))
+	}
+}
+
+func TestSuspendAccountGRPC(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@suspect", Status: domain.StatusActive}
+	client := startGRPCClient(t, grpcRepoStub{account: acc})
+
+	resp, err := client.SuspendAccount(context.Background(), &accountv1.UpdateStatusRequest{
+		Id:     acc.ID.String(),
+		Reason: accountv1.StatusReason_STATUS_REASON_SPAM,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.GetAccount().GetStatus() != accountv1.AccountStatus_ACCOUNT_STATUS_SUSPENDED {
+		t.Fatalf("expected suspended status, got %v", resp.GetAccount().GetStatus())
+	}
+	if resp.GetAccount().GetStatusReason() != accountv1.StatusReason_STATUS_REASON_SPAM {
+		t.Fatalf("expected spam reason, got %v", resp.GetAccount().GetStatusReason())
+	}
+}
+
+func TestStatusGRPCErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		reason accountv1.StatusReason
+		code   codes.Code
+	}{
+		{"unspecified reason", nil, accountv1.StatusReason_STATUS_REASON_UNSPECIFIED, codes.InvalidArgument},
+		{"invalid transition", domain.ErrInvalidStatusTransition, accountv1.StatusReason_STATUS_REASON_APPEAL_GRANTED, codes.FailedPrecondition},
+		{"not found", domain.ErrAccountNotFound, accountv1.StatusReason_STATUS_REASON_APPEAL_GRANTED, codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := startGRPCClient(t, grpcRepoStub{err: tt.err})
+
+			_, err := client.ReinstateAccount(context.Background(), &accountv1.UpdateStatusRequest{Id: uuid.New().String(), Reason: tt.reason})
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected %v, got %v", tt.code, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestUpdateNickGRPCInactiveAccount(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{err: domain.ErrAccountNotActive})
+
+	_, err := client.UpdateNick(context.Background(), &accountv1.UpdateNickRequest{Id: uuid.New().String(), Nick: "@renamed"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", status.Code(err))
+	}
+}
+
+func TestUpdateProfileGRPCNormalizesFields(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@profiled", Profile: domain.Profile{Bio: "unchanged"}}
+	client := startGRPCClient(t, grpcRepoStub{account: acc})
+
+	resp, err := client.UpdateProfile(context.Background(), &accountv1.UpdateProfileRequest{
+		Id:          acc.ID.String(),
+		DisplayName: proto.String("  Zoe\u0301  "),
+		Locale:      proto.String("en_us"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	profile := resp.GetAccount().GetProfile()
+	if profile.GetDisplayName() != "Zo\u00e9" || profile.GetLocale() != "en-US" || profile.GetBio() != "unchanged" {
+		t.Fatalf("unexpected profile: %v", profile)
+	}
+}
+
+func TestUpdateProfileGRPCRejectsInvalidField(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{})
+
+	_, err := client.UpdateProfile(context.Background(), &accountv1.UpdateProfileRequest{
+		Id:       uuid.New().String(),
+		TimeZone: proto.String("Mars/Olympus_Mons"),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", status.Code(err))
+	}
+}
+
+func TestGetAccountByNickGRPC(t *testing.T) {
+	acc := domain.Account{ID: uuid.New(), Nick: "@current"}
+	client := startGRPCClient(t, grpcRepoStub{account: acc})
+
+	resp, err := client.GetAccountByNick(context.Background(), &accountv1.GetAccountByNickRequest{Nick: "@former"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetAccount().GetId() != acc.ID.String() {
+		t.Fatalf("expected id %s, got %s", acc.ID, resp.GetAccount().GetId())
+	}
+
+	_, err = client.GetAccountByNick(context.Background(), &accountv1.GetAccountByNickRequest{Nick: "no-at-sign"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", status.Code(err))
+	}
+}
+
+func TestUpdateNickGRPCCooldown(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{err: domain.ErrNickInCooldown})
+
+	_, err := client.UpdateNick(context.Background(), &accountv1.UpdateNickRequest{Id: uuid.New().String(), Nick: "@released"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", status.Code(err))
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+func TestCheckNickAvailable(t *testing.T) {
+	id := uuid.New()
+	calls := 0
+	svc := accountsvc.New(fakeRepo{
+		checkNicksFn: func(_ context.Context, gotID uuid.UUID, nicks []string) (map[string]domain.NickCheckReason, error) {
+			calls++
+			if gotID != id || nicks[0] != "@alice" {
+				t.Fatalf("unexpected CheckNicks(%s, %v)", gotID, nicks)
+			}
+			return map[string]domain.NickCheckReason{}, nil
+		},
+	})
+
+	check, err := svc.CheckNick(context.Background(), id, " @alice ", 0)
+	if err != nil {
+		t.Fatalf("CheckNick failed: %v", err)
+	}
+	if !check.Valid || !check.Available || len(check.Reasons) != 0 || len(check.Suggestions) != 0 || check.Nick != "@alice" {
+		t.Fatalf("unexpected check: %+v", check)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one repository query, got %d", calls)
+	}
+}
+
+func TestCheckNickSuggestsAvailableAlternatives(t *testing.T) {
+	calls := 0
+	svc := accountsvc.New(fakeRepo{
+		checkNicksFn: func(_ context.Context, _ uuid.UUID, nicks []string) (map[string]domain.NickCheckReason, error) {
+			calls++
+			return map[string]domain.NickCheckReason{
+				"@alice99":  domain.NickCheckTaken,
+				"@alice_99": domain.NickCheckCooldown,
+			}, nil
+		},
+	})
+
+	check, err := svc.CheckNick(context.Background(), uuid.Nil, "@Alice99", 3)
+	if err != nil {
+		t.Fatalf("CheckNick failed: %v", err)
+	}
+	if !check.Valid || check.Available || !slices.Equal(check.Reasons, []domain.NickCheckReason{domain.NickCheckTaken}) {
+		t.Fatalf("unexpected check: %+v", check)
+	}
+	if len(check.Suggestions) != 3 || calls != 1 {
+		t.Fatalf("expected 3 suggestions from one query, got %v from %d", check.Suggestions, calls)
+	}
+	for _, s := range check.Suggestions {
+		if strings.EqualFold(s, "@alice_99") || strings.EqualFold(s, "@alice99") || !strings.HasPrefix(s, "@Alice") {
+			t.Fatalf("unexpected suggestion %s in %v", s, check.Suggestions)
+		}
+		if sc, err := svc.CheckNick(context.Background(), uuid.Nil, s, 1); err != nil || !sc.Valid {
+			t.Fatalf("expected suggestion %s to be valid, got %+v, %v", s, sc, err)
+		}
+	}
+}
+
+func TestCheckNickReportsReasons(t *testing.T) {
+	ctx := context.Background()
+	svc := accountsvc.New(fakeRepo{
+		checkNicksFn: func(context.Context, uuid.UUID, []string) (map[string]domain.NickCheckReason, error) {
+			t.Fatal("expected no repository query without candidates")
+			return nil, nil
+		},
+	}, accountsvc.WithNickRules(newSQLiteRepo(t)), accountsvc.WithNickFilter(testWordlistFilter()))
+	if _, err := svc.AddNickRule(ctx, domain.NickRule{Kind: domain.NickRulePrefix, Value: "staff"}); err != nil {
+		t.Fatalf("AddNickRule failed: %v", err)
+	}
+
+	check, err := svc.CheckNick(ctx, uuid.Nil, "@x", 0)
+	if err != nil {
+		t.Fatalf("CheckNick failed: %v", err)
+	}
+	if check.Valid || check.Available || !slices.Equal(check.Reasons, []domain.NickCheckReason{domain.NickCheckInvalidFormat}) {
+		t.Fatalf("unexpected check for invalid nick: %+v", check)
+	}
+
+	// Every candidate derived from the nick keeps the reserved prefix.
+	check, err = svc.CheckNick(ctx, uuid.Nil, "@staff_admin", 0)
+	if err != nil {
+		t.Fatalf("CheckNick failed: %v", err)
+	}
+	want := []domain.NickCheckReason{domain.NickCheckReserved, domain.NickCheckNotAllowed}
+	if check.Valid || check.Available || !slices.Equal(check.Reasons, want) || len(check.Suggestions) != 0 {
+		t.Fatalf("unexpected check for reserved nick: %+v", check)
+	}
+}
+
+func TestCheckNickGRPC(t *testing.T) {
+	ctx := context.Background()
+	client := startGRPCClient(t, grpcRepoStub{account: domain.Account{ID: uuid.New(), Nick: "@taken"}})
+
+	resp, err := client.CheckNick(ctx, &accountv1.CheckNickRequest{Nick: "@Taken", MaxSuggestions: 2})
+	if err != nil {
+		t.Fatalf("CheckNick failed: %v", err)
+	}
+	if !resp.GetValid() || resp.GetAvailable() || !slices.Equal(resp.GetReasons(), []accountv1.NickCheckReason{accountv1.NickCheckReason_NICK_CHECK_REASON_TAKEN}) {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+	if len(resp.GetSuggestions()) != 2 {
+		t.Fatalf("expected 2 suggestions, got %v", resp.GetSuggestions())
+	}
+
+	resp, err = client.CheckNick(ctx, &accountv1.CheckNickRequest{Nick: "@free", AccountId: uuid.New().String()})
+	if err != nil || !resp.GetAvailable() {
+		t.Fatalf("expected @free to be available, got %v, %v", resp, err)
+	}
+
+	_, err = client.CheckNick(ctx, &accountv1.CheckNickRequest{Nick: "@free", AccountId: "not-a-uuid"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for bad account id, got %v", status.Code(err))
+	}
+}
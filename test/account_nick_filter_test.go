@@ -0,0 +1,198 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/config"
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+type nickFilterFunc func(ctx context.Context, nick string) error
+
+func (f nickFilterFunc) Check(ctx context.Context, nick string) error {
+	return f(ctx, nick)
+}
+
+func testWordlistFilter() *accountsvc.WordlistFilter {
+	return accountsvc.NewWordlistFilter(map[string][]string{
+		"profanity":     {"fuck", "=ass"},
+		"impersonation": {"admin"},
+	}, []string{"badminton"})
+}
+
+func TestWordlistFilterNormalizesEvasions(t *testing.T) {
+	filter := testWordlistFilter()
+
+	rejected := map[string]string{
+		"@fuck":      "profanity",
+		"@FuCk_you":  "profanity",
+		"@f_u_c_k":   "profanity",
+		"@fuuuuuck":  "profanity",
+		"@ass":       "profanity",
+		"@a55":       "profanity",
+		"@4dm1n":     "impersonation",
+		"@the_adm1n": "impersonation",
+		"@аdmіn":     "impersonation", // Cyrillic а and і
+		"@ádmìn":     "impersonation",
+		"@ＡＤＭＩＮ":     "impersonation", // full-width
+	}
+	for nick, category := range rejected {
+		err := filter.Check(context.Background(), nick)
+		var notAllowed *domain.NickNotAllowedError
+		if !errors.As(err, &notAllowed) || notAllowed.Category != category {
+			t.Fatalf("expected %s to be rejected as %s, got %v", nick, category, err)
+		}
+		if !errors.Is(err, domain.ErrNickNotAllowed) {
+			t.Fatalf("expected %v to match ErrNickNotAllowed", err)
+		}
+	}
+
+	for _, nick := range []string{"@classic", "@bassist", "@badminton_club", "@alice"} {
+		if err := filter.Check(context.Background(), nick); err != nil {
+			t.Fatalf("expected %s to pass, got %v", nick, err)
+		}
+	}
+}
+
+// defaultWordlistFilter builds the filter from the built-in wordlists.
+func defaultWordlistFilter(t *testing.T) *accountsvc.WordlistFilter {
+	t.Helper()
+
+	read := func(name string) []string {
+		f, err := config.NickFilterFiles.Open("nick_filter/" + name + ".txt")
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		defer f.Close()
+		terms, err := accountsvc.ParseWordlist(f)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		return terms
+	}
+
+	return accountsvc.NewWordlistFilter(map[string][]string{
+		"profanity":     read("profanity"),
+		"impersonation": read("impersonation"),
+	}, read("allow"))
+}
+
+func TestDefaultWordlistFilterAcceptsOrdinaryNicks(t *testing.T) {
+	filter := defaultWordlistFilter(t)
+
+	for _, nick := range []string{
+		"@nightwatch", "@saltwater", "@shilton", "@dickinson", "@dickens", "@cocktail_bar",
+		"@shilton1", "@scunthorpe99", "@glass_2", "@shilton_99",
+	} {
+		if err := filter.Check(context.Background(), nick); err != nil {
+			t.Fatalf("expected %s to pass, got %v", nick, err)
+		}
+	}
+
+	for _, nick := range []string{
+		"@sh1t", "@shit_head", "@s1ut", "@$lut", "@twat", "@dick", "@glass_asshole", "@wanker",
+		"@glasshole", "@twatwatch", "@watch_twat_water", "@twatwater", "@scunthorpe_cunt", "@dickinson_dick",
+	} {
+		if err := filter.Check(context.Background(), nick); !errors.Is(err, domain.ErrNickNotAllowed) {
+			t.Fatalf("expected %s to be rejected, got %v", nick, err)
+		}
+	}
+}
+
+func TestUpdateNickRejectsFilteredNick(t *testing.T) {
+	svc := accountsvc.New(fakeRepo{
+		updateNickFn: func(context.Context, uuid.UUID, string) (domain.Account, error) {
+			t.Fatal("expected filtered nick not to reach the repository")
+			return domain.Account{}, nil
+		},
+	}, accountsvc.WithNickFilter(testWordlistFilter()))
+
+	_, err := svc.UpdateNick(context.Background(), uuid.New(), "@sup3r_adm1n")
+	if !errors.Is(err, domain.ErrNickNotAllowed) {
+		t.Fatalf("expected ErrNickNotAllowed, got %v", err)
+	}
+}
+
+func TestCreateRegeneratesFilteredNick(t *testing.T) {
+	checks := 0
+	filter := nickFilterFunc(func(context.Context, string) error {
+		checks++
+		if checks < 3 {
+			return &domain.NickNotAllowedError{Category: "profanity"}
+		}
+		return nil
+	})
+
+	var created []string
+	svc := accountsvc.New(fakeRepo{
+		createFn: func(_ context.Context, id uuid.UUID, nick, phone string) (domain.Account, error) {
+			created = append(created, nick)
+			return domain.Account{ID: id, Nick: nick, Phone: phone}, nil
+		},
+	}, accountsvc.WithNickFilter(filter))
+
+	if _, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+15551234567"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if checks != 3 || len(created) != 1 {
+		t.Fatalf("expected 3 checks and 1 insert, got %d checks and %v", checks, created)
+	}
+}
+
+func TestCreateFailsWhenFilterFails(t *testing.T) {
+	filterErr := errors.New("filter unavailable")
+	svc := accountsvc.New(fakeRepo{}, accountsvc.WithNickFilter(nickFilterFunc(func(context.Context, string) error {
+		return filterErr
+	})))
+
+	if _, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+15551234567"}); !errors.Is(err, filterErr) {
+		t.Fatalf("expected filter error, got %v", err)
+	}
+}
+
+func TestDefaultNickFilterWordlistsParse(t *testing.T) {
+	for _, name := range []string{"nick_filter/profanity.txt", "nick_filter/impersonation.txt", "nick_filter/allow.txt"} {
+		f, err := config.NickFilterFiles.Open(name)
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		terms, err := accountsvc.ParseWordlist(f)
+		f.Close()
+		if err != nil || len(terms) == 0 {
+			t.Fatalf("expected terms in %s, got %v, %v", name, terms, err)
+		}
+	}
+}
+
+func TestUpdateNickGRPCFilteredNickDetails(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{}, accountsvc.WithNickFilter(testWordlistFilter()))
+
+	_, err := client.UpdateNick(context.Background(), &accountv1.UpdateNickRequest{Id: uuid.New().String(), Nick: "@f_u_c_k"})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", st.Code())
+	}
+
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if i, ok := d.(*errdetails.ErrorInfo); ok {
+			info = i
+		}
+	}
+	if info == nil {
+		t.Fatalf("expected ErrorInfo detail, got %v", st.Details())
+	}
+	if info.GetReason() != grpcapi.ReasonNickNotAllowed || info.GetDomain() != grpcapi.ErrorDomain || info.GetMetadata()["category"] != "profanity" {
+		t.Fatalf("unexpected ErrorInfo: %v", info)
+	}
+}
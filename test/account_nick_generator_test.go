@@ -0,0 +1,209 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kvetinski/account/config"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+type nickGeneratorFunc func(ctx context.Context, locale string, collisions int) (string, error)
+
+func (f nickGeneratorFunc) Generate(ctx context.Context, locale string, collisions int) (string, error) {
+	return f(ctx, locale, collisions)
+}
+
+var testNickWords = map[string]accountsvc.NickWords{
+	"en": {Adjectives: []string{"brave"}, Nouns: []string{"otter"}},
+	"de": {Adjectives: []string{"Mutig"}, Nouns: []string{"Bär"}},
+}
+
+func TestWordlistNickGeneratorPicksLocale(t *testing.T) {
+	gen, err := accountsvc.NewWordlistNickGenerator(testNickWords, "en", 4)
+	if err != nil {
+		t.Fatalf("NewWordlistNickGenerator failed: %v", err)
+	}
+
+	cases := []struct {
+		locale     string
+		collisions int
+		want       string
+	}{
+		{"", 0, `^@brave_otter[0-9]{4}$`},
+		{"de-AT", 0, `^@mutig_bar[0-9]{4}$`},
+		{"fr", 0, `^@brave_otter[0-9]{4}$`},
+		{"not a locale", 0, `^@brave_otter[0-9]{4}$`},
+		{"de", 2, `^@mutig_bar[0-9]{6}$`},
+		{"en", 10, `^@brave_otter[0-9]{8}$`},
+	}
+	for _, tc := range cases {
+		nick, err := gen.Generate(context.Background(), tc.locale, tc.collisions)
+		if err != nil {
+			t.Fatalf("Generate(%q, %d) failed: %v", tc.locale, tc.collisions, err)
+		}
+		if !regexp.MustCompile(tc.want).MatchString(nick) {
+			t.Fatalf("Generate(%q, %d) = %s, want %s", tc.locale, tc.collisions, nick, tc.want)
+		}
+	}
+}
+
+func TestNewWordlistNickGeneratorRejectsBadWords(t *testing.T) {
+	if _, err := accountsvc.NewWordlistNickGenerator(testNickWords, "es", 4); err == nil {
+		t.Fatal("expected error for missing default locale")
+	}
+	if _, err := accountsvc.NewWordlistNickGenerator(testNickWords, "en", 9); err == nil {
+		t.Fatal("expected error for too many digits")
+	}
+	words := map[string]accountsvc.NickWords{"en": {Adjectives: []string{"über-cool", "extraordinarily"}, Nouns: []string{"otter"}}}
+	if _, err := accountsvc.NewWordlistNickGenerator(words, "en", 4); err == nil {
+		t.Fatal("expected error when no adjective survives folding")
+	}
+}
+
+func TestRandomNickGeneratorGrowsWithCollisions(t *testing.T) {
+	gen := accountsvc.NewRandomNickGenerator(0)
+	for collisions, want := range map[int]int{0: 10, 3: 13, 50: 30} {
+		nick, err := gen.Generate(context.Background(), "", collisions)
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		if len(nick) != want+1 || !regexp.MustCompile(`^@[a-z0-9]+$`).MatchString(nick) {
+			t.Fatalf("expected %d random characters after @, got %s", want, nick)
+		}
+	}
+}
+
+// Every combination of the built-in words must be a valid nick that the
+// built-in content filter accepts, or Create would waste attempts on it.
+func TestDefaultNickWordsPassDefaultFilter(t *testing.T) {
+	fsys, err := fs.Sub(config.NickWordFiles, "nick_words")
+	if err != nil {
+		t.Fatalf("open nick words: %v", err)
+	}
+	words, err := accountsvc.LoadNickWords(fsys)
+	if err != nil {
+		t.Fatalf("LoadNickWords failed: %v", err)
+	}
+	for _, locale := range []string{"en", "de", "es"} {
+		if _, err = accountsvc.NewWordlistNickGenerator(words, locale, 0); err != nil {
+			t.Fatalf("expected built-in words for %s to be usable: %v", locale, err)
+		}
+	}
+
+	read := func(name string) []string {
+		f, err := config.NickFilterFiles.Open(name)
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		defer f.Close()
+		terms, err := accountsvc.ParseWordlist(f)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		return terms
+	}
+	filter := accountsvc.NewWordlistFilter(map[string][]string{
+		"profanity":     read("nick_filter/profanity.txt"),
+		"impersonation": read("nick_filter/impersonation.txt"),
+	}, read("nick_filter/allow.txt"))
+	svc := accountsvc.New(fakeRepo{
+		checkNicksFn: func(context.Context, uuid.UUID, []string) (map[string]domain.NickCheckReason, error) {
+			return nil, nil
+		},
+	}, accountsvc.WithNickFilter(filter))
+
+	for locale, w := range words {
+		gen, err := accountsvc.NewWordlistNickGenerator(map[string]accountsvc.NickWords{locale: w}, locale, 0)
+		if err != nil {
+			t.Fatalf("NewWordlistNickGenerator(%s) failed: %v", locale, err)
+		}
+		seen := make(map[string]bool)
+		// Enough draws to cover every pair of these short lists many times over.
+		for range 20 * len(w.Adjectives) * len(w.Nouns) {
+			nick, err := gen.Generate(context.Background(), "", 0)
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			if seen[nick] {
+				continue
+			}
+			seen[nick] = true
+			check, err := svc.CheckNick(context.Background(), uuid.Nil, nick, 1)
+			if err != nil || !check.Valid {
+				t.Fatalf("expected generated nick %s to be valid, got %+v, %v", nick, check, err)
+			}
+		}
+	}
+}
+
+func TestCreateRetriesGeneratorAfterCollisions(t *testing.T) {
+	var got []string
+	gen := nickGeneratorFunc(func(_ context.Context, locale string, collisions int) (string, error) {
+		got = append(got, fmt.Sprintf("%s/%d", locale, collisions))
+		return fmt.Sprintf("@gen_%d", collisions), nil
+	})
+
+	registry := prometheus.NewRegistry()
+	svc := accountsvc.New(fakeRepo{
+		createFn: func(_ context.Context, id uuid.UUID, nick, phone string) (domain.Account, error) {
+			switch nick {
+			case "@gen_0":
+				return domain.Account{}, domain.ErrNickAlreadyExists
+			case "@gen_1":
+				return domain.Account{}, domain.ErrNickInCooldown
+			}
+			return domain.Account{ID: id, Nick: nick, Phone: phone}, nil
+		},
+	}, accountsvc.WithNickGenerator(gen, 5), accountsvc.WithMetrics(telemetry.NewMetrics(registry)))
+
+	acc, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+15551234567", Locale: "de_at"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if acc.Nick != "@gen_2" || strings.Join(got, ",") != "de-AT/0,de-AT/1,de-AT/2" {
+		t.Fatalf("expected third proposal after two collisions, got %s from %v", acc.Nick, got)
+	}
+
+	expected := `
+# HELP account_nick_generations_total Total generated nick proposals by result, and account creations that ran out of proposals.
+# TYPE account_nick_generations_total counter
+account_nick_generations_total{result="claimed"} 1
+account_nick_generations_total{result="collision"} 2
+`
+	if err = testutil.GatherAndCompare(registry, strings.NewReader(expected), "account_nick_generations_total"); err != nil {
+		t.Fatalf("unexpected nick generation metric: %v", err)
+	}
+}
+
+func TestCreateGivesUpAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	svc := accountsvc.New(fakeRepo{
+		createFn: func(context.Context, uuid.UUID, string, string) (domain.Account, error) {
+			attempts++
+			return domain.Account{}, domain.ErrNickAlreadyExists
+		},
+	}, accountsvc.WithNickGenerator(accountsvc.NewRandomNickGenerator(0), 3))
+
+	if _, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+15551234567"}); !errors.Is(err, domain.ErrNickAlreadyExists) {
+		t.Fatalf("expected ErrNickAlreadyExists, got %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+
+	if _, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+15551234567", Locale: "!!"}); !errors.Is(err, domain.ErrInvalidLocale) {
+		t.Fatalf("expected ErrInvalidLocale, got %v", err)
+	}
+}
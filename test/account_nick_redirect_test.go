@@ -0,0 +1,51 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+// testNickRedirectSkipsDeletedHolder checks that a released nick redirects to
+// the latest holder that still exists. It runs against every storage driver.
+func testNickRedirectSkipsDeletedHolder(t *testing.T, repo accountsvc.Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	first, err := repo.Create(ctx, uuid.New(), "@shared", domain.Phone{Number: "+15550000701"}, 0)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err = repo.UpdateNick(ctx, first.ID, "@first", 0); err != nil {
+		t.Fatalf("UpdateNick failed: %v", err)
+	}
+	second, err := repo.Create(ctx, uuid.New(), "@shared", domain.Phone{Number: "+15550000702"}, 0)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err = repo.UpdateNick(ctx, second.ID, "@second", 0); err != nil {
+		t.Fatalf("UpdateNick failed: %v", err)
+	}
+
+	got, err := repo.GetByNick(ctx, "@shared", time.Hour)
+	if err != nil || got.ID != second.ID {
+		t.Fatalf("expected redirect to the latest holder %s, got %+v, %v", second.ID, got, err)
+	}
+
+	if err = repo.Delete(ctx, second.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	got, err = repo.GetByNick(ctx, "@SHARED", time.Hour)
+	if err != nil || got.ID != first.ID {
+		t.Fatalf("expected redirect to fall back to %s, got %+v, %v", first.ID, got, err)
+	}
+}
+
+func TestSQLiteNickRedirectSkipsDeletedHolder(t *testing.T) {
+	testNickRedirectSkipsDeletedHolder(t, newSQLiteRepo(t))
+}
@@ -0,0 +1,270 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/config"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+func TestNickRulesRejectMatchingNicks(t *testing.T) {
+	ctx := context.Background()
+	svc := accountsvc.New(fakeRepo{
+		updateNickFn: func(_ context.Context, id uuid.UUID, nick string) (domain.Account, error) {
+			return domain.Account{ID: id, Nick: nick}, nil
+		},
+	}, accountsvc.WithNickRules(newSQLiteRepo(t)))
+
+	rules := []domain.NickRule{
+		{Kind: domain.NickRuleExact, Value: "@Admin"},
+		{Kind: domain.NickRulePrefix, Value: "official"},
+		{Kind: domain.NickRulePattern, Value: `^support[0-9]+$`},
+	}
+	if err := svc.SeedNickRules(ctx, rules); err != nil {
+		t.Fatalf("SeedNickRules failed: %v", err)
+	}
+
+	for _, nick := range []string{"@admin", "@ADMIN", "@official_news", "@Support42"} {
+		if _, err := svc.UpdateNick(ctx, uuid.New(), nick); !errors.Is(err, domain.ErrNickReserved) {
+			t.Fatalf("expected ErrNickReserved for %s, got %v", nick, err)
+		}
+	}
+	for _, nick := range []string{"@admins", "@unofficial", "@support", "@support42x"} {
+		if _, err := svc.UpdateNick(ctx, uuid.New(), nick); err != nil {
+			t.Fatalf("expected %s to be allowed, got %v", nick, err)
+		}
+	}
+}
+
+func TestNickRulesAddAndRemove(t *testing.T) {
+	ctx := context.Background()
+	svc := accountsvc.New(fakeRepo{
+		updateNickFn: func(_ context.Context, id uuid.UUID, nick string) (domain.Account, error) {
+			return domain.Account{ID: id, Nick: nick}, nil
+		},
+	}, accountsvc.WithNickRules(newSQLiteRepo(t)))
+
+	rule, err := svc.AddNickRule(ctx, domain.NickRule{Kind: domain.NickRuleExact, Value: " @Brand ", Reason: " trademark "})
+	if err != nil {
+		t.Fatalf("AddNickRule failed: %v", err)
+	}
+	if rule.ID == uuid.Nil || rule.Value != "brand" || rule.Reason != "trademark" || rule.CreatedAt.IsZero() {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+	if _, err = svc.UpdateNick(ctx, uuid.New(), "@brand"); !errors.Is(err, domain.ErrNickReserved) {
+		t.Fatalf("expected ErrNickReserved right after AddNickRule, got %v", err)
+	}
+	if _, err = svc.AddNickRule(ctx, domain.NickRule{Kind: domain.NickRuleExact, Value: "brand"}); !errors.Is(err, domain.ErrNickRuleExists) {
+		t.Fatalf("expected ErrNickRuleExists, got %v", err)
+	}
+
+	if err = svc.RemoveNickRule(ctx, rule.ID); err != nil {
+		t.Fatalf("RemoveNickRule failed: %v", err)
+	}
+	if _, err = svc.UpdateNick(ctx, uuid.New(), "@brand"); err != nil {
+		t.Fatalf("expected nick to be allowed after removal, got %v", err)
+	}
+	if err = svc.RemoveNickRule(ctx, rule.ID); !errors.Is(err, domain.ErrNickRuleNotFound) {
+		t.Fatalf("expected ErrNickRuleNotFound, got %v", err)
+	}
+
+	rules, err := svc.ListNickRules(ctx)
+	if err != nil || len(rules) != 0 {
+		t.Fatalf("expected no rules, got %+v, %v", rules, err)
+	}
+}
+
+// failingListRuleStore stores rules but fails to list them.
+type failingListRuleStore struct {
+	accountsvc.NickRuleStore
+}
+
+func (failingListRuleStore) ListNickRules(context.Context) ([]domain.NickRule, error) {
+	return nil, errors.New("replica unavailable")
+}
+
+func TestNickRulesWriteSucceedsWhenRefreshFails(t *testing.T) {
+	ctx := context.Background()
+	registry := prometheus.NewRegistry()
+	svc := accountsvc.New(fakeRepo{},
+		accountsvc.WithNickRules(failingListRuleStore{newSQLiteRepo(t)}),
+		accountsvc.WithMetrics(telemetry.NewMetrics(registry)))
+
+	rule, err := svc.AddNickRule(ctx, domain.NickRule{Kind: domain.NickRuleExact, Value: "brand"})
+	if err != nil {
+		t.Fatalf("expected the stored rule despite the failed refresh, got %v", err)
+	}
+	if rule.ID == uuid.Nil || rule.Value != "brand" {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+	if err = svc.RemoveNickRule(ctx, rule.ID); err != nil {
+		t.Fatalf("expected removal to succeed despite the failed refresh, got %v", err)
+	}
+
+	expected := `
+# HELP account_nick_rule_refresh_failures_total Total failed reloads of the nick rules, after a rule change on this replica or by the periodic sync.
+# TYPE account_nick_rule_refresh_failures_total counter
+account_nick_rule_refresh_failures_total{trigger="write"} 2
+`
+	if err = testutil.GatherAndCompare(registry, strings.NewReader(expected), "account_nick_rule_refresh_failures_total"); err != nil {
+		t.Fatalf("unexpected refresh failure metric: %v", err)
+	}
+}
+
+func TestNickRulesRejectInvalidRules(t *testing.T) {
+	svc := accountsvc.New(fakeRepo{}, accountsvc.WithNickRules(newSQLiteRepo(t)))
+
+	for _, rule := range []domain.NickRule{
+		{Kind: domain.NickRuleExact, Value: ""},
+		{Kind: domain.NickRuleExact, Value: "no spaces"},
+		{Kind: domain.NickRulePrefix, Value: "bad-char"},
+		{Kind: domain.NickRulePattern, Value: "("},
+		{Kind: "suffix", Value: "bot"},
+	} {
+		if _, err := svc.AddNickRule(context.Background(), rule); !errors.Is(err, domain.ErrInvalidNickRule) {
+			t.Fatalf("expected ErrInvalidNickRule for %+v, got %v", rule, err)
+		}
+	}
+}
+
+func TestCreateRegeneratesNickMatchingRule(t *testing.T) {
+	ctx := context.Background()
+	var nicks []string
+	svc := accountsvc.New(fakeRepo{
+		createFn: func(_ context.Context, id uuid.UUID, nick, phone string) (domain.Account, error) {
+			nicks = append(nicks, nick)
+			return domain.Account{ID: id, Nick: nick, Phone: phone}, nil
+		},
+	}, accountsvc.WithNickRules(newSQLiteRepo(t)))
+
+	if _, err := svc.AddNickRule(ctx, domain.NickRule{Kind: domain.NickRulePattern, Value: "^[a-m]"}); err != nil {
+		t.Fatalf("AddNickRule failed: %v", err)
+	}
+
+	for range 20 {
+		if _, err := svc.Create(ctx, domain.NewAccount{Phone: "+15551234567"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	for _, nick := range nicks {
+		if strings.IndexByte("abcdefghijklm", nick[1]) >= 0 {
+			t.Fatalf("expected generated nick %s not to match the rule", nick)
+		}
+	}
+}
+
+func TestNickRulesDisabledWithoutStore(t *testing.T) {
+	svc := accountsvc.New(fakeRepo{})
+
+	if _, err := svc.ListNickRules(context.Background()); !errors.Is(err, domain.ErrNickRulesDisabled) {
+		t.Fatalf("expected ErrNickRulesDisabled, got %v", err)
+	}
+}
+
+func TestParseNickRules(t *testing.T) {
+	input := `
+# comment
+exact admin staff account
+prefix official
+
+pattern ^mod[0-9]+$ impersonation
+`
+	rules, err := accountsvc.ParseNickRules(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseNickRules failed: %v", err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 rules, got %+v", rules)
+	}
+	if rules[0].Kind != domain.NickRuleExact || rules[0].Value != "admin" || rules[0].Reason != "staff account" {
+		t.Fatalf("unexpected first rule: %+v", rules[0])
+	}
+	if rules[2].Kind != domain.NickRulePattern || rules[2].Value != "^mod[0-9]+$" {
+		t.Fatalf("unexpected last rule: %+v", rules[2])
+	}
+
+	if _, err = accountsvc.ParseNickRules(strings.NewReader("exact\n")); err == nil {
+		t.Fatal("expected error for line without value")
+	}
+}
+
+func TestNickRulesGRPC(t *testing.T) {
+	ctx := context.Background()
+	client := startGRPCClient(t, grpcRepoStub{account: domain.Account{ID: uuid.New(), Nick: "@current"}}, accountsvc.WithNickRules(newSQLiteRepo(t)))
+
+	rule, err := client.AddNickRule(ctx, &accountv1.AddNickRuleRequest{Kind: accountv1.NickRuleKind_NICK_RULE_KIND_PREFIX, Value: "staff_"})
+	if err != nil {
+		t.Fatalf("AddNickRule failed: %v", err)
+	}
+	if rule.GetKind() != accountv1.NickRuleKind_NICK_RULE_KIND_PREFIX || rule.GetValue() != "staff_" {
+		t.Fatalf("unexpected rule: %v", rule)
+	}
+
+	_, err = client.AddNickRule(ctx, &accountv1.AddNickRuleRequest{Kind: accountv1.NickRuleKind_NICK_RULE_KIND_PREFIX, Value: "staff_"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", status.Code(err))
+	}
+	_, err = client.AddNickRule(ctx, &accountv1.AddNickRuleRequest{Value: "unspecified"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for unspecified kind, got %v", status.Code(err))
+	}
+
+	_, err = client.UpdateNick(ctx, &accountv1.UpdateNickRequest{Id: uuid.New().String(), Nick: "@staff_jane"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for reserved nick, got %v", status.Code(err))
+	}
+
+	list, err := client.ListNickRules(ctx, &accountv1.ListNickRulesRequest{})
+	if err != nil || len(list.GetRules()) != 1 {
+		t.Fatalf("expected one rule, got %v, %v", list, err)
+	}
+
+	if _, err = client.RemoveNickRule(ctx, &accountv1.RemoveNickRuleRequest{Id: rule.GetId()}); err != nil {
+		t.Fatalf("RemoveNickRule failed: %v", err)
+	}
+	_, err = client.RemoveNickRule(ctx, &accountv1.RemoveNickRuleRequest{Id: rule.GetId()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", status.Code(err))
+	}
+}
+
+func TestNickRulesGRPCDisabled(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{})
+
+	_, err := client.ListNickRules(context.Background(), &accountv1.ListNickRulesRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected Unimplemented, got %v", status.Code(err))
+	}
+}
+
+func TestDefaultNickRulesSeed(t *testing.T) {
+	rules, err := accountsvc.ParseNickRules(bytes.NewReader(config.DefaultNickRules))
+	if err != nil {
+		t.Fatalf("ParseNickRules failed: %v", err)
+	}
+
+	svc := accountsvc.New(fakeRepo{}, accountsvc.WithNickRules(newSQLiteRepo(t)))
+	if err = svc.SeedNickRules(context.Background(), rules); err != nil {
+		t.Fatalf("SeedNickRules failed: %v", err)
+	}
+	// Seeding is idempotent across restarts.
+	if err = svc.SeedNickRules(context.Background(), rules); err != nil {
+		t.Fatalf("second SeedNickRules failed: %v", err)
+	}
+	if _, err = svc.UpdateNick(context.Background(), uuid.New(), "@admin"); !errors.Is(err, domain.ErrNickReserved) {
+		t.Fatalf("expected @admin to be reserved by default, got %v", err)
+	}
+}
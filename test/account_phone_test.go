@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	"github.com/kvetinski/account/internal/phone"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+func TestPhoneParse(t *testing.T) {
+	cases := []struct {
+		raw, region  string
+		e164, parsed string
+	}{
+		{"+1 (555) 123-4567", "", "+15551234567", "US"},
+		{"+1 416 555 0100", "", "+14165550100", "CA"},
+		{"+44 20 7183 8750", "", "+442071838750", "GB"},
+		{"0044 20 7183 8750", "", "+442071838750", "GB"},
+		{"011 44 20 7183 8750", "US", "+442071838750", "GB"},
+		{"020 7183 8750", "GB", "+442071838750", "GB"},
+		{"(030) 123 4567", "de", "+49301234567", "DE"},
+		{"1-555-123-4567", "US", "+15551234567", "US"},
+		{"555.123.4567", "US", "+15551234567", "US"},
+		{"06 12 34 56 78", "FR", "+33612345678", "FR"},
+		{"8 (800) 555-35-35", "RU", "+78005553535", "RU"},
+		{"+7 701 123 4567", "", "+77011234567", "KZ"},
+		{"+39 06 1234 5678", "", "+390612345678", "IT"},
+		{"+420 601 123 456", "", "+420601123456", "CZ"},
+		{"+372 5123 4567", "", "+37251234567", ""}, // calling code not in the dataset
+	}
+	for _, tc := range cases {
+		n, err := phone.Parse(tc.raw, tc.region)
+		if err != nil {
+			t.Fatalf("Parse(%q, %q) failed: %v", tc.raw, tc.region, err)
+		}
+		if n.E164() != tc.e164 || n.Region != tc.parsed {
+			t.Fatalf("Parse(%q, %q) = %s %s, want %s %s", tc.raw, tc.region, n.E164(), n.Region, tc.e164, tc.parsed)
+		}
+	}
+
+	rejected := []struct {
+		raw, region string
+		err         error
+	}{
+		{"+1 555 123 456", "", phone.ErrInvalidNumber},
+		{"+1 055 123 4567", "", phone.ErrInvalidNumber},
+		{"+44 20 7183 87501 2", "", phone.ErrInvalidNumber},
+		{"+49 30 12", "", phone.ErrInvalidNumber},
+		{"+0 123 4567 890", "", phone.ErrInvalidNumber},
+		{"020 7183 8750", "", phone.ErrUnknownRegion},
+		{"020 7183 8750", "XX", phone.ErrUnknownRegion},
+		{"+44 20 CALL NOW", "", phone.ErrInvalidFormat},
+		{"", "", phone.ErrInvalidFormat},
+	}
+	for _, tc := range rejected {
+		if _, err := phone.Parse(tc.raw, tc.region); !errors.Is(err, tc.err) {
+			t.Fatalf("Parse(%q, %q): expected %v, got %v", tc.raw, tc.region, tc.err, err)
+		}
+	}
+}
+
+func TestCreateNormalizesPhone(t *testing.T) {
+	var stored []string
+	svc := accountsvc.New(fakeRepo{
+		createFn: func(_ context.Context, id uuid.UUID, nick, phone string) (domain.Account, error) {
+			stored = append(stored, phone)
+			return domain.Account{ID: id, Nick: nick, Phone: phone}, nil
+		},
+	}, accountsvc.WithPhoneRegion("GB"))
+
+	for _, raw := range []string{"+44 20 7183 8750", "020 7183 8750", "(020) 7183-8750", "0044 2071838750"} {
+		acc, err := svc.Create(context.Background(), domain.NewAccount{Phone: raw})
+		if err != nil || acc.Phone != "+442071838750" {
+			t.Fatalf("expected %q to be stored as +442071838750, got %+v, %v", raw, acc, err)
+		}
+	}
+
+	if _, err := svc.Create(context.Background(), domain.NewAccount{Phone: "020 718"}); !errors.Is(err, domain.ErrInvalidPhone) {
+		t.Fatalf("expected ErrInvalidPhone, got %v", err)
+	}
+}
+
+func TestCreateRejectsDifferentFormattingsOfOnePhone(t *testing.T) {
+	svc := accountsvc.New(newSQLiteRepo(t))
+
+	if _, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+1 (555) 010-0001"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+1.555.010.0001"}); !errors.Is(err, domain.ErrPhoneAlreadyExists) {
+		t.Fatalf("expected ErrPhoneAlreadyExists, got %v", err)
+	}
+}
+
+func TestGetAccountGRPCPhoneCountry(t *testing.T) {
+	client := startGRPCClient(t, grpcRepoStub{account: domain.Account{ID: uuid.New(), Nick: "@prague", Phone: "+420601123456"}})
+
+	resp, err := client.GetAccount(context.Background(), &accountv1.GetAccountRequest{Id: uuid.New().String()})
+	if err != nil {
+		t.Fatalf("GetAccount failed: %v", err)
+	}
+	if resp.GetAccount().GetPhoneCountry() != "CZ" {
+		t.Fatalf("expected phone country CZ, got %q", resp.GetAccount().GetPhoneCountry())
+	}
+}
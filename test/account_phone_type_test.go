@@ -0,0 +1,193 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/config"
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+const testNumberingPlan = `
+# test plan
++1555 fixed_line
++15550 mobile
++15551 voip
++1900 premium_rate
+`
+
+func newTestNumberingPlan(t *testing.T) *accountsvc.NumberingPlan {
+	t.Helper()
+
+	plan, err := accountsvc.ParseNumberingPlan(strings.NewReader(testNumberingPlan))
+	if err != nil {
+		t.Fatalf("ParseNumberingPlan failed: %v", err)
+	}
+
+	return plan
+}
+
+func TestNumberingPlanClassify(t *testing.T) {
+	ctx := context.Background()
+	plan := newTestNumberingPlan(t)
+
+	cases := map[string]domain.PhoneType{
+		"+15550000001":  domain.PhoneTypeMobile,
+		"+15551000001":  domain.PhoneTypeVoIP,
+		"+15559000001":  domain.PhoneTypeFixedLine,
+		"+19005550100":  domain.PhoneTypePremiumRate,
+		"+442071838750": domain.PhoneTypeUnknown,
+	}
+	for number, want := range cases {
+		if got, err := plan.Classify(ctx, number); err != nil || got != want {
+			t.Fatalf("Classify(%s) = %s, %v; want %s", number, got, err, want)
+		}
+	}
+
+	// A dataset that fails to parse keeps the loaded one.
+	if err := plan.Load(strings.NewReader("+1555 satellite\n")); err == nil {
+		t.Fatal("expected unknown type to fail")
+	}
+	if got, _ := plan.Classify(ctx, "+15551000001"); got != domain.PhoneTypeVoIP {
+		t.Fatalf("expected the previous plan after a failed load, got %s", got)
+	}
+
+	if err := plan.Load(strings.NewReader("+1555 toll_free\n")); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got, _ := plan.Classify(ctx, "+15551000001"); got != domain.PhoneTypeTollFree {
+		t.Fatalf("expected the reloaded plan, got %s", got)
+	}
+}
+
+func TestParseNumberingPlanErrors(t *testing.T) {
+	for _, plan := range []string{
+		"1555 mobile",
+		"+15a5 mobile",
+		"+1555",
+		"+1555 mobile extra",
+		"+1555 mobile\n+1555 voip",
+	} {
+		if _, err := accountsvc.ParseNumberingPlan(strings.NewReader(plan)); err == nil {
+			t.Fatalf("expected %q to fail", plan)
+		}
+	}
+}
+
+func TestDefaultNumberingPlan(t *testing.T) {
+	ctx := context.Background()
+	plan, err := accountsvc.ParseNumberingPlan(bytes.NewReader(config.DefaultNumberingPlan))
+	if err != nil {
+		t.Fatalf("parse default numbering plan: %v", err)
+	}
+
+	cases := map[string]domain.PhoneType{
+		"+447911123456": domain.PhoneTypeMobile,
+		"+447011123456": domain.PhoneTypeVoIP,
+		"+447624123456": domain.PhoneTypeMobile, // Isle of Man
+		"+445612345678": domain.PhoneTypeVoIP,
+		"+442071838750": domain.PhoneTypeFixedLine,
+		"+448001234567": domain.PhoneTypeTollFree,
+		"+19005550100":  domain.PhoneTypePremiumRate,
+		"+15551234567":  domain.PhoneTypeUnknown,
+	}
+	for number, want := range cases {
+		if got, err := plan.Classify(ctx, number); err != nil || got != want {
+			t.Fatalf("Classify(%s) = %s, %v; want %s", number, got, err, want)
+		}
+	}
+}
+
+func TestCreateAppliesPhoneTypePolicy(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLiteRepo(t)
+	svc := accountsvc.New(repo, accountsvc.WithPhoneClassifier(newTestNumberingPlan(t), accountsvc.PhoneTypePolicy{
+		Reject: []domain.PhoneType{domain.PhoneTypePremiumRate},
+		Flag:   []domain.PhoneType{domain.PhoneTypeVoIP},
+	}))
+
+	_, err := svc.Create(ctx, domain.NewAccount{Phone: "+1 900 555 0100"})
+	var notAllowed *domain.PhoneTypeNotAllowedError
+	if !errors.Is(err, domain.ErrPhoneTypeNotAllowed) || !errors.As(err, &notAllowed) || notAllowed.Type != domain.PhoneTypePremiumRate {
+		t.Fatalf("expected premium rate number to be rejected, got %v", err)
+	}
+
+	flagged, err := svc.Create(ctx, domain.NewAccount{Phone: "+15551000001"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if flagged.PhoneType != domain.PhoneTypeVoIP || !flagged.PhoneFlagged {
+		t.Fatalf("expected flagged VoIP account, got %+v", flagged)
+	}
+
+	accepted, err := svc.Create(ctx, domain.NewAccount{Phone: "+15550000001"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if accepted.PhoneType != domain.PhoneTypeMobile || accepted.PhoneFlagged {
+		t.Fatalf("expected accepted mobile account, got %+v", accepted)
+	}
+
+	stored, err := repo.GetByID(ctx, flagged.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if stored.PhoneType != domain.PhoneTypeVoIP || !stored.PhoneFlagged {
+		t.Fatalf("expected stored classification, got %+v", stored)
+	}
+}
+
+func TestCreateWithoutPhoneClassifier(t *testing.T) {
+	acc, err := accountsvc.New(newSQLiteRepo(t)).Create(context.Background(), domain.NewAccount{Phone: "+19005550100"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if acc.PhoneType != domain.PhoneTypeUnknown || acc.PhoneFlagged {
+		t.Fatalf("expected unclassified account, got %+v", acc)
+	}
+}
+
+func TestCreateAccountGRPCPhoneType(t *testing.T) {
+	ctx := context.Background()
+	client := startGRPCClient(t, newSQLiteRepo(t), accountsvc.WithPhoneClassifier(newTestNumberingPlan(t), accountsvc.PhoneTypePolicy{
+		Reject: []domain.PhoneType{domain.PhoneTypePremiumRate},
+		Flag:   []domain.PhoneType{domain.PhoneTypeVoIP},
+	}))
+
+	resp, err := client.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: "+15551000001"})
+	if err != nil {
+		t.Fatalf("CreateAccount failed: %v", err)
+	}
+	if resp.GetAccount().GetPhoneType() != accountv1.PhoneType_PHONE_TYPE_VOIP || !resp.GetAccount().GetPhoneFlagged() {
+		t.Fatalf("unexpected account: %v", resp.GetAccount())
+	}
+
+	_, err = client.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: "+19005550100"})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", st.Code())
+	}
+
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if i, ok := d.(*errdetails.ErrorInfo); ok {
+			info = i
+		}
+	}
+	if info == nil {
+		t.Fatalf("expected ErrorInfo detail, got %v", st.Details())
+	}
+	if info.GetReason() != grpcapi.ReasonPhoneTypeNotAllowed || info.GetDomain() != grpcapi.ErrorDomain || info.GetMetadata()["type"] != "premium_rate" {
+		t.Fatalf("unexpected ErrorInfo: %v", info)
+	}
+}
@@ -0,0 +1,194 @@
+package test
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+	"github.com/kvetinski/account/internal/telemetry"
+)
+
+func TestParseRates(t *testing.T) {
+	rates, err := grpcapi.ParseRates([]string{"*=100:200", "CreateAccount=0.5:5"})
+	if err != nil {
+		t.Fatalf("ParseRates failed: %v", err)
+	}
+	if rates["*"] != (grpcapi.Rate{PerSecond: 100, Burst: 200}) || rates["CreateAccount"] != (grpcapi.Rate{PerSecond: 0.5, Burst: 5}) {
+		t.Fatalf("unexpected rates: %v", rates)
+	}
+
+	for _, entry := range []string{"CreateAccount", "CreateAccount=5", "=1:1", "CreateAccount=x:1", "CreateAccount=1:-1"} {
+		if _, err = grpcapi.ParseRates([]string{entry}); err == nil {
+			t.Fatalf("expected %q to fail", entry)
+		}
+	}
+}
+
+// startRateLimitedClient serves over TCP on 127.0.0.1, so that the limiter
+// sees the client IP.
+func startRateLimitedClient(t *testing.T, cfg grpcapi.RateLimitConfig, metrics *telemetry.Metrics) accountv1.AccountServiceClient {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	limiter := grpcapi.NewRateLimiter(cfg, metrics)
+	svc := accountsvc.New(grpcRepoStub{account: domain.Account{ID: uuid.New(), Nick: "@limited"}})
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(limiter.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(limiter.StreamInterceptor()))
+	accountv1.RegisterAccountServiceServer(s, grpcapi.NewServer(svc, slog.Default()))
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return accountv1.NewAccountServiceClient(conn)
+}
+
+func TestRateLimitPerMethod(t *testing.T) {
+	ctx := context.Background()
+	registry := prometheus.NewRegistry()
+	client := startRateLimitedClient(t, grpcapi.RateLimitConfig{
+		Methods: map[string]grpcapi.Rate{"GetAccountByNick": {PerSecond: 0.001, Burst: 2}},
+	}, telemetry.NewMetrics(registry))
+
+	for range 2 {
+		if _, err := client.GetAccountByNick(ctx, &accountv1.GetAccountByNickRequest{Nick: "@limited"}); err != nil {
+			t.Fatalf("GetAccountByNick failed: %v", err)
+		}
+	}
+
+	_, err := client.GetAccountByNick(ctx, &accountv1.GetAccountByNickRequest{Nick: "@limited"})
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", st.Code())
+	}
+	var retry *errdetails.RetryInfo
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.RetryInfo:
+			retry = d
+		case *errdetails.ErrorInfo:
+			info = d
+		}
+	}
+	if retry == nil || retry.GetRetryDelay().AsDuration() <= 0 {
+		t.Fatalf("expected a positive RetryInfo delay, got %v", retry)
+	}
+	if info == nil || info.GetReason() != grpcapi.ReasonRateLimited || info.GetMetadata()["scope"] != grpcapi.RateLimitScopeMethod {
+		t.Fatalf("unexpected ErrorInfo: %v", info)
+	}
+
+	// Other methods have no quota.
+	if _, err = client.CheckNick(ctx, &accountv1.CheckNickRequest{Nick: "@free"}); err != nil {
+		t.Fatalf("expected CheckNick to be allowed, got %v", err)
+	}
+
+	expected := `
+# HELP account_grpc_rate_limited_total Total gRPC requests rejected by the rate limiter by method and scope.
+# TYPE account_grpc_rate_limited_total counter
+account_grpc_rate_limited_total{method="GetAccountByNick",scope="method"} 1
+`
+	if err = testutil.GatherAndCompare(registry, strings.NewReader(expected), "account_grpc_rate_limited_total"); err != nil {
+		t.Fatalf("unexpected metrics: %v", err)
+	}
+}
+
+func TestRateLimitPerClient(t *testing.T) {
+	client := startRateLimitedClient(t, grpcapi.RateLimitConfig{
+		Clients:        map[string]grpcapi.Rate{grpcapi.DefaultRateLimitMethod: {PerSecond: 0.001, Burst: 1}},
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")},
+	}, nil)
+
+	call := func(callerID string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), grpcapi.CallerIDHeader, callerID)
+		_, err := client.GetAccountByNick(ctx, &accountv1.GetAccountByNickRequest{Nick: "@limited"})
+		return err
+	}
+
+	if err := call("team-a"); err != nil {
+		t.Fatalf("GetAccountByNick failed: %v", err)
+	}
+	if err := call("team-a"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected team-a to be limited, got %v", err)
+	}
+	if err := call("team-b"); err != nil {
+		t.Fatalf("expected team-b to have its own quota, got %v", err)
+	}
+
+	// Each method has its own bucket per client.
+	ctx := metadata.AppendToOutgoingContext(context.Background(), grpcapi.CallerIDHeader, "team-a")
+	if _, err := client.CheckNick(ctx, &accountv1.CheckNickRequest{Nick: "@free"}); err != nil {
+		t.Fatalf("expected another method to be allowed, got %v", err)
+	}
+}
+
+func TestRateLimitPerClientIgnoresUntrustedCallerID(t *testing.T) {
+	client := startRateLimitedClient(t, grpcapi.RateLimitConfig{
+		Clients: map[string]grpcapi.Rate{grpcapi.DefaultRateLimitMethod: {PerSecond: 0.001, Burst: 1}},
+	}, nil)
+
+	call := func(callerID string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), grpcapi.CallerIDHeader, callerID)
+		_, err := client.GetAccountByNick(ctx, &accountv1.GetAccountByNickRequest{Nick: "@limited"})
+		return err
+	}
+
+	if err := call("random-1"); err != nil {
+		t.Fatalf("GetAccountByNick failed: %v", err)
+	}
+	// Without a trusted proxy, a new caller id does not get a new bucket.
+	if err := call("random-2"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected the client IP to be limited, got %v", err)
+	}
+}
+
+func TestRateLimitRejectedRequestKeepsOtherQuota(t *testing.T) {
+	client := startRateLimitedClient(t, grpcapi.RateLimitConfig{
+		Methods: map[string]grpcapi.Rate{grpcapi.DefaultRateLimitMethod: {PerSecond: 10, Burst: 1}},
+		Clients: map[string]grpcapi.Rate{grpcapi.DefaultRateLimitMethod: {PerSecond: 0.001, Burst: 2}},
+	}, nil)
+	ctx := context.Background()
+
+	if _, err := client.CheckNick(ctx, &accountv1.CheckNickRequest{Nick: "@one"}); err != nil {
+		t.Fatalf("CheckNick failed: %v", err)
+	}
+	if _, err := client.CheckNick(ctx, &accountv1.CheckNickRequest{Nick: "@two"}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected the shared quota to be exhausted, got %v", err)
+	}
+
+	// Once the shared bucket refills, the client still has the token the
+	// rejected request did not take.
+	time.Sleep(150 * time.Millisecond)
+	if _, err := client.CheckNick(ctx, &accountv1.CheckNickRequest{Nick: "@three"}); err != nil {
+		t.Fatalf("expected the client quota to be left, got %v", err)
+	}
+}
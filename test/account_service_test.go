@@ -2,42 +2,80 @@ package test
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/kvetinski/account/internal/domain"
 	accountsvc "github.com/kvetinski/account/internal/service/account"
+	"github.com/kvetinski/account/internal/telemetry"
 )
 
 type fakeRepo struct {
 	createFn     func(ctx context.Context, id uuid.UUID, nick, phone string) (domain.Account, error)
 	getByIDFn    func(ctx context.Context, id uuid.UUID) (domain.Account, error)
+	getByNickFn  func(ctx context.Context, nick string, redirectPeriod time.Duration) (domain.Account, error)
+	historyFn    func(ctx context.Context, id uuid.UUID) ([]domain.NickChange, error)
+	checkNicksFn func(ctx context.Context, id uuid.UUID, nicks []string) (map[string]domain.NickCheckReason, error)
 	updateNickFn func(ctx context.Context, id uuid.UUID, nick string) (domain.Account, error)
+	profileFn    func(ctx context.Context, id uuid.UUID, update domain.ProfileUpdate) (domain.Account, error)
+	statusFn     func(ctx context.Context, id uuid.UUID, to domain.Status, reason domain.StatusReason, from ...domain.Status) (domain.Account, error)
 	deleteFn     func(ctx context.Context, id uuid.UUID) error
+	tokenFn      func(ctx context.Context) (string, error)
 }
 
-func (f fakeRepo) Create(ctx context.Context, id uuid.UUID, nick, phone string) (domain.Account, error) {
-	return f.createFn(ctx, id, nick, phone)
+func (f fakeRepo) Create(ctx context.Context, id uuid.UUID, nick string, phone domain.Phone, _ time.Duration) (domain.Account, error) {
+	return f.createFn(ctx, id, nick, phone.Number)
 }
 
 func (f fakeRepo) GetByID(ctx context.Context, id uuid.UUID) (domain.Account, error) {
 	return f.getByIDFn(ctx, id)
 }
 
-func (f fakeRepo) UpdateNick(ctx context.Context, id uuid.UUID, nick string) (domain.Account, error) {
+func (f fakeRepo) GetByNick(ctx context.Context, nick string, redirectPeriod time.Duration) (domain.Account, error) {
+	return f.getByNickFn(ctx, nick, redirectPeriod)
+}
+
+func (f fakeRepo) ListNickHistory(ctx context.Context, id uuid.UUID) ([]domain.NickChange, error) {
+	return f.historyFn(ctx, id)
+}
+
+func (f fakeRepo) CheckNicks(ctx context.Context, id uuid.UUID, nicks []string, _ time.Duration) (map[string]domain.NickCheckReason, error) {
+	return f.checkNicksFn(ctx, id, nicks)
+}
+
+func (f fakeRepo) UpdateNick(ctx context.Context, id uuid.UUID, nick string, _ time.Duration) (domain.Account, error) {
 	return f.updateNickFn(ctx, id, nick)
 }
 
+func (f fakeRepo) UpdateProfile(ctx context.Context, id uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
+	return f.profileFn(ctx, id, update)
+}
+
+func (f fakeRepo) UpdateStatus(ctx context.Context, id uuid.UUID, to domain.Status, reason domain.StatusReason, from ...domain.Status) (domain.Account, error) {
+	return f.statusFn(ctx, id, to, reason, from...)
+}
+
 func (f fakeRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	return f.deleteFn(ctx, id)
 }
 
+func (f fakeRepo) ConsistencyToken(ctx context.Context) (string, error) {
+	return f.tokenFn(ctx)
+}
+
 func TestCreateRejectsInvalidPhone(t *testing.T) {
 	svc := accountsvc.New(fakeRepo{})
 
-	_, err := svc.Create(context.Background(), "123")
+	_, err := svc.Create(context.Background(), domain.NewAccount{Phone: "123"})
 	if err != domain.ErrInvalidPhone {
 		t.Fatalf("expected ErrInvalidPhone, got %v", err)
 	}
@@ -55,7 +93,7 @@ func TestCreateGeneratesNickAndPassesPhoneToRepo(t *testing.T) {
 		},
 	})
 
-	acc, err := svc.Create(context.Background(), "+15551234567")
+	acc, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+15551234567"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -115,3 +153,258 @@ func TestDeleteForwardsRepoError(t *testing.T) {
 		t.Fatalf("expected ErrAccountNotFound, got %v", err)
 	}
 }
+
+func TestUpdateProfileValidation(t *testing.T) {
+	var got domain.ProfileUpdate
+	svc := accountsvc.New(fakeRepo{
+		profileFn: func(_ context.Context, id uuid.UUID, update domain.ProfileUpdate) (domain.Account, error) {
+			got = update
+			return domain.Account{ID: id}, nil
+		},
+	})
+
+	ptr := func(s string) *string { return &s }
+	tests := []struct {
+		name    string
+		update  domain.ProfileUpdate
+		want    domain.ProfileUpdate
+		wantErr error
+	}{
+		{"display name is NFC normalized", domain.ProfileUpdate{DisplayName: ptr(" Cafe\u0301 ")}, domain.ProfileUpdate{DisplayName: ptr("Caf\u00e9")}, nil},
+		{"display name too long", domain.ProfileUpdate{DisplayName: ptr(strings.Repeat("é", 65))}, domain.ProfileUpdate{}, domain.ErrInvalidDisplayName},
+		{"display name control character", domain.ProfileUpdate{DisplayName: ptr("a\tb")}, domain.ProfileUpdate{}, domain.ErrInvalidDisplayName},
+		{"bio keeps newlines", domain.ProfileUpdate{Bio: ptr("line one\nline two")}, domain.ProfileUpdate{Bio: ptr("line one\nline two")}, nil},
+		{"bio invalid utf-8", domain.ProfileUpdate{Bio: ptr("\xff")}, domain.ProfileUpdate{}, domain.ErrInvalidBio},
+		{"avatar scheme lowercased", domain.ProfileUpdate{AvatarURL: ptr("HTTPS://cdn.example.com/a.png")}, domain.ProfileUpdate{AvatarURL: ptr("https://cdn.example.com/a.png")}, nil},
+		{"avatar scheme not allowed", domain.ProfileUpdate{AvatarURL: ptr("javascript:alert(1)")}, domain.ProfileUpdate{}, domain.ErrInvalidAvatarURL},
+		{"avatar plain http", domain.ProfileUpdate{AvatarURL: ptr("http://cdn.example.com/a.png")}, domain.ProfileUpdate{}, domain.ErrInvalidAvatarURL},
+		{"avatar with credentials", domain.ProfileUpdate{AvatarURL: ptr("https://user:pw@cdn.example.com/a.png")}, domain.ProfileUpdate{}, domain.ErrInvalidAvatarURL},
+		{"locale canonicalized", domain.ProfileUpdate{Locale: ptr("pt_br")}, domain.ProfileUpdate{Locale: ptr("pt-BR")}, nil},
+		{"locale invalid", domain.ProfileUpdate{Locale: ptr("not a locale")}, domain.ProfileUpdate{}, domain.ErrInvalidLocale},
+		{"time zone", domain.ProfileUpdate{TimeZone: ptr("Europe/Prague")}, domain.ProfileUpdate{TimeZone: ptr("Europe/Prague")}, nil},
+		{"time zone unknown", domain.ProfileUpdate{TimeZone: ptr("Europe/Atlantis")}, domain.ProfileUpdate{}, domain.ErrInvalidTimeZone},
+		{"time zone local", domain.ProfileUpdate{TimeZone: ptr("Local")}, domain.ProfileUpdate{}, domain.ErrInvalidTimeZone},
+		{"empty clears", domain.ProfileUpdate{Locale: ptr("  ")}, domain.ProfileUpdate{Locale: ptr("")}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = domain.ProfileUpdate{}
+			_, err := svc.UpdateProfile(context.Background(), uuid.New(), tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			for _, f := range []struct{ got, want *string }{
+				{got.DisplayName, tt.want.DisplayName},
+				{got.Bio, tt.want.Bio},
+				{got.AvatarURL, tt.want.AvatarURL},
+				{got.Locale, tt.want.Locale},
+				{got.TimeZone, tt.want.TimeZone},
+			} {
+				if (f.got == nil) != (f.want == nil) || (f.got != nil && *f.got != *f.want) {
+					t.Fatalf("unexpected update passed to repository: %+v", got)
+				}
+			}
+		})
+	}
+}
+
+func TestGetByNickUsesConfiguredRedirectPeriod(t *testing.T) {
+	var gotPeriod time.Duration
+	svc := accountsvc.New(fakeRepo{
+		getByNickFn: func(_ context.Context, nick string, redirectPeriod time.Duration) (domain.Account, error) {
+			gotPeriod = redirectPeriod
+			return domain.Account{Nick: nick}, nil
+		},
+	}, accountsvc.WithNickHistory(accountsvc.NickHistoryConfig{RedirectPeriod: time.Hour}))
+
+	if _, err := svc.GetByNick(context.Background(), " @someone "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPeriod != time.Hour {
+		t.Fatalf("expected redirect period 1h, got %s", gotPeriod)
+	}
+}
+
+func TestCreateRetriesNickInCooldown(t *testing.T) {
+	calls := 0
+	svc := accountsvc.New(fakeRepo{
+		createFn: func(_ context.Context, id uuid.UUID, nick, phone string) (domain.Account, error) {
+			calls++
+			if calls == 1 {
+				return domain.Account{}, domain.ErrNickInCooldown
+			}
+			return domain.Account{ID: id, Nick: nick, Phone: phone}, nil
+		},
+	})
+
+	if _, err := svc.Create(context.Background(), domain.NewAccount{Phone: "+15551234567"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected a new nick to be generated after a cooldown conflict, got %d calls", calls)
+	}
+}
+
+func TestStatusTransitionsRestrictSourceStatuses(t *testing.T) {
+	var gotTo domain.Status
+	var gotFrom []domain.Status
+	svc := accountsvc.New(fakeRepo{
+		statusFn: func(_ context.Context, id uuid.UUID, to domain.Status, reason domain.StatusReason, from ...domain.Status) (domain.Account, error) {
+			gotTo, gotFrom = to, from
+			return domain.Account{ID: id, Status: to, StatusReason: reason}, nil
+		},
+	})
+
+	tests := []struct {
+		name       string
+		transition func(context.Context, uuid.UUID, domain.StatusReason) (domain.Account, error)
+		to         domain.Status
+		from       []domain.Status
+	}{
+		{"suspend", svc.Suspend, domain.StatusSuspended, []domain.Status{domain.StatusActive}},
+		{"reinstate", svc.Reinstate, domain.StatusActive, []domain.Status{domain.StatusSuspended}},
+		{"ban", svc.Ban, domain.StatusBanned, []domain.Status{domain.StatusActive, domain.StatusSuspended}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acc, err := tt.transition(context.Background(), uuid.New(), domain.StatusReasonSpam)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if acc.Status != tt.to || acc.StatusReason != domain.StatusReasonSpam {
+				t.Fatalf("unexpected account: %#v", acc)
+			}
+			if gotTo != tt.to || !slices.Equal(gotFrom, tt.from) {
+				t.Fatalf("expected transition %v -> %s, got %v -> %s", tt.from, tt.to, gotFrom, gotTo)
+			}
+		})
+	}
+}
+
+func TestStatusTransitionRejectsInvalidReason(t *testing.T) {
+	svc := accountsvc.New(fakeRepo{})
+
+	_, err := svc.Suspend(context.Background(), uuid.New(), "")
+	if !errors.Is(err, domain.ErrInvalidStatusReason) {
+		t.Fatalf("expected ErrInvalidStatusReason, got %v", err)
+	}
+}
+
+func TestGetByIDCoalescesConcurrentReads(t *testing.T) {
+	id := uuid.New()
+	release := make(chan struct{})
+	var calls atomic.Int32
+	registry := prometheus.NewRegistry()
+	metrics := telemetry.NewMetrics(registry)
+	svc := accountsvc.New(fakeRepo{
+		getByIDFn: func(_ context.Context, gotID uuid.UUID) (domain.Account, error) {
+			calls.Add(1)
+			<-release
+			return domain.Account{ID: gotID, Nick: "@celebrity"}, nil
+		},
+	}, accountsvc.WithMetrics(metrics))
+
+	const callers = 10
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			acc, err := svc.GetByID(context.Background(), id)
+			if err != nil || acc.ID != id {
+				t.Errorf("unexpected result: %+v, %v", acc, err)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 repository call, got %d", got)
+	}
+	expected := `
+# HELP account_coalesced_requests_total Total service reads answered by an identical in-flight read instead of the repository.
+# TYPE account_coalesced_requests_total counter
+account_coalesced_requests_total{method="get_by_id"} 9
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "account_coalesced_requests_total"); err != nil {
+		t.Fatalf("unexpected coalesced metric: %v", err)
+	}
+}
+
+func TestGetByIDDoesNotShareReadsAcrossConsistencyTokens(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	svc := accountsvc.New(fakeRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (domain.Account, error) {
+			calls.Add(1)
+			<-release
+			return domain.Account{ID: id}, nil
+		},
+	})
+
+	id := uuid.New()
+	var wg sync.WaitGroup
+	for _, token := range []string{"", "token-a", "token-b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.GetByID(domain.WithConsistencyToken(context.Background(), token), id); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected one repository call per token, got %d", got)
+	}
+}
+
+func TestGetByIDCanceledCallerDoesNotFailOthers(t *testing.T) {
+	release := make(chan struct{})
+	svc := accountsvc.New(fakeRepo{
+		getByIDFn: func(ctx context.Context, id uuid.UUID) (domain.Account, error) {
+			<-release
+			if err := ctx.Err(); err != nil {
+				return domain.Account{}, err
+			}
+			return domain.Account{ID: id}, nil
+		},
+	})
+
+	id := uuid.New()
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := svc.GetByID(leaderCtx, id)
+		leaderErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	followerErr := make(chan error, 1)
+	go func() {
+		_, err := svc.GetByID(context.Background(), id)
+		followerErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancelLeader()
+	if err := <-leaderErr; err != context.Canceled {
+		t.Fatalf("expected leader to observe its cancellation, got %v", err)
+	}
+
+	close(release)
+	if err := <-followerErr; err != nil {
+		t.Fatalf("expected follower to get the shared result, got %v", err)
+	}
+}
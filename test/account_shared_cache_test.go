@@ -0,0 +1,238 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/kvetinski/account/internal/adapters/cache"
+	"github.com/kvetinski/account/internal/domain"
+)
+
+var sharedCacheConfig = cache.SharedConfig{
+	TTL:         time.Minute,
+	NegativeTTL: time.Minute,
+	LockTTL:     time.Second,
+	LockWait:    time.Second,
+}
+
+func startRedisClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return server, client
+}
+
+func TestSharedCacheIsSharedBetweenInstances(t *testing.T) {
+	_, client := startRedisClient(t)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	acc := domain.Account{ID: uuid.New(), Nick: "@shared", Phone: "+15551234567", CreatedAt: now, UpdatedAt: now}
+
+	calls := 0
+	first := cache.NewShared(countingGetRepo(&calls, acc, nil), client, sharedCacheConfig, nil)
+	second := cache.NewShared(countingGetRepo(&calls, acc, nil), client, sharedCacheConfig, nil)
+
+	if _, err := first.GetByID(context.Background(), acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := second.GetByID(context.Background(), acc.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected second instance to be served from redis, got %d repository calls", calls)
+	}
+	if got.ID != acc.ID || got.Nick != acc.Nick || got.Phone != acc.Phone || !got.CreatedAt.Equal(acc.CreatedAt) || got.DeletedAt != nil {
+		t.Fatalf("account did not survive serialization: %+v", got)
+	}
+}
+
+func TestSharedCacheNegativeCaching(t *testing.T) {
+	_, client := startRedisClient(t)
+	calls := 0
+	repo := cache.NewShared(countingGetRepo(&calls, domain.Account{}, domain.ErrAccountNotFound), client, sharedCacheConfig, nil)
+
+	id := uuid.New()
+	for range 2 {
+		if _, err := repo.GetByID(context.Background(), id); !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Fatalf("expected ErrAccountNotFound, got %v", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected not-found to be cached, got %d repository calls", calls)
+	}
+}
+
+func TestSharedCacheMutationInvalidatesForAllInstances(t *testing.T) {
+	_, client := startRedisClient(t)
+	acc := domain.Account{ID: uuid.New(), Nick: "@before"}
+
+	calls := 0
+	writer := cache.NewShared(countingGetRepo(&calls, acc, nil), client, sharedCacheConfig, nil)
+	reader := cache.NewShared(countingGetRepo(&calls, acc, nil), client, sharedCacheConfig, nil)
+	ctx := context.Background()
+
+	if _, err := reader.GetByID(ctx, acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := writer.UpdateNick(ctx, acc.ID, "@after", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := reader.GetByID(ctx, acc.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected reader to refetch after update, got %d repository calls", calls)
+	}
+}
+
+func TestSharedCacheCollapsesStampede(t *testing.T) {
+	_, client := startRedisClient(t)
+	acc := domain.Account{ID: uuid.New(), Nick: "@celebrity"}
+
+	var calls atomic.Int32
+	repo := cache.NewShared(fakeRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (domain.Account, error) {
+			calls.Add(1)
+			time.Sleep(50 * time.Millisecond)
+			return acc, nil
+		},
+	}, client, sharedCacheConfig, nil)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.GetByID(context.Background(), acc.ID); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected concurrent misses to load once, got %d repository calls", got)
+	}
+}
+
+func TestSharedCacheFallsBackWhenRedisIsDown(t *testing.T) {
+	server, client := startRedisClient(t)
+	server.Close()
+
+	acc := domain.Account{ID: uuid.New(), Nick: "@resilient"}
+	calls := 0
+	repo := cache.NewShared(countingGetRepo(&calls, acc, nil), client, sharedCacheConfig, nil)
+
+	got, err := repo.GetByID(context.Background(), acc.ID)
+	if err != nil {
+		t.Fatalf("expected repository fallback, got %v", err)
+	}
+	if got.Nick != acc.Nick || calls != 1 {
+		t.Fatalf("expected account from repository, got %+v after %d calls", got, calls)
+	}
+}
+
+func TestSharedCacheFillRacingInvalidationIsDropped(t *testing.T) {
+	_, client := startRedisClient(t)
+	id := uuid.New()
+
+	var calls atomic.Int32
+	reading := make(chan struct{})
+	release := make(chan struct{})
+	repo := cache.NewShared(fakeRepo{
+		getByIDFn: func(context.Context, uuid.UUID) (domain.Account, error) {
+			if calls.Add(1) == 1 {
+				// The first read sees the row before the update commits.
+				close(reading)
+				<-release
+				return domain.Account{ID: id, Nick: "@before"}, nil
+			}
+			return domain.Account{ID: id, Nick: "@after"}, nil
+		},
+		updateNickFn: func(_ context.Context, id uuid.UUID, nick string) (domain.Account, error) {
+			return domain.Account{ID: id, Nick: nick}, nil
+		},
+	}, client, sharedCacheConfig, nil)
+	ctx := context.Background()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := repo.GetByID(ctx, id)
+		done <- err
+	}()
+	<-reading
+	if _, err := repo.UpdateNick(ctx, id, "@after", 0); err != nil {
+		t.Fatalf("UpdateNick failed: %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.Nick != "@after" {
+		t.Fatalf("expected the stale fill to be dropped, got %q", got.Nick)
+	}
+}
+
+func TestSharedCacheGetByNick(t *testing.T) {
+	_, client := startRedisClient(t)
+	acc := domain.Account{ID: uuid.New(), Nick: "@Holder"}
+
+	var byNick, byID int
+	repo := cache.NewShared(fakeRepo{
+		getByIDFn: func(context.Context, uuid.UUID) (domain.Account, error) {
+			byID++
+			return acc, nil
+		},
+		getByNickFn: func(context.Context, string, time.Duration) (domain.Account, error) {
+			byNick++
+			return acc, nil
+		},
+		updateNickFn: func(_ context.Context, id uuid.UUID, nick string) (domain.Account, error) {
+			acc.Nick = nick
+			return acc, nil
+		},
+	}, client, sharedCacheConfig, nil)
+	ctx := context.Background()
+
+	for _, nick := range []string{"@Holder", "@holder", "@HOLDER"} {
+		got, err := repo.GetByNick(ctx, nick, time.Hour)
+		if err != nil || got.ID != acc.ID {
+			t.Fatalf("GetByNick(%s) = %+v, %v", nick, got, err)
+		}
+	}
+	if byNick != 1 || byID != 1 {
+		t.Fatalf("expected one repository read per path, got %d by nick and %d by id", byNick, byID)
+	}
+
+	// Once the account releases the nick, the index no longer matches and
+	// the repository resolves the redirect.
+	if _, err := repo.UpdateNick(ctx, acc.ID, "@renamed", 0); err != nil {
+		t.Fatalf("UpdateNick failed: %v", err)
+	}
+	got, err := repo.GetByNick(ctx, "@holder", time.Hour)
+	if err != nil || got.Nick != "@renamed" {
+		t.Fatalf("expected the redirect from the repository, got %+v, %v", got, err)
+	}
+	if byNick != 2 {
+		t.Fatalf("expected a released nick to be read from the repository, got %d reads", byNick)
+	}
+}
@@ -0,0 +1,402 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kvetinski/account/internal/adapters/repository/sqlite"
+	"github.com/kvetinski/account/internal/domain"
+)
+
+func newSQLiteRepo(t *testing.T) *sqlite.Repository {
+	t.Helper()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "account.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = sqlite.Migrate(ctx, db); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+	if err = sqlite.Migrate(ctx, db); err != nil {
+		t.Fatalf("re-running migrations should be a no-op: %v", err)
+	}
+
+	return sqlite.New(db)
+}
+
+func TestSQLiteCreateGetUpdateDelete(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	ctx := context.Background()
+
+	id := uuid.New()
+	created, err := repo.Create(ctx, id, "@lite_first", domain.Phone{Number: "+15550000201"}, 0)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.ID != id || created.DeletedAt != nil {
+		t.Fatalf("unexpected created account: %+v", created)
+	}
+
+	got, err := repo.GetByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.Nick != "@lite_first" || got.Phone != "+15550000201" {
+		t.Fatalf("unexpected account from GetByID: %+v", got)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	updated, err := repo.UpdateNick(ctx, id, "@lite_second", 0)
+	if err != nil {
+		t.Fatalf("UpdateNick failed: %v", err)
+	}
+	if updated.Nick != "@lite_second" {
+		t.Fatalf("expected nick @lite_second, got %s", updated.Nick)
+	}
+	if !updated.UpdatedAt.After(created.UpdatedAt) {
+		t.Fatalf("expected updated_at to move forward, before=%s after=%s", created.UpdatedAt, updated.UpdatedAt)
+	}
+
+	if err = repo.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	_, err = repo.GetByID(ctx, id)
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Fatalf("expected ErrAccountNotFound after delete, got %v", err)
+	}
+
+	err = repo.Delete(ctx, id)
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Fatalf("expected ErrAccountNotFound on second delete, got %v", err)
+	}
+}
+
+func TestSQLiteConstraintViolations(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	ctx := context.Background()
+
+	first, err := repo.Create(ctx, uuid.New(), "@lite_taken", domain.Phone{Number: "+15550000202"}, 0)
+	if err != nil {
+		t.Fatalf("first Create failed: %v", err)
+	}
+
+	_, err = repo.Create(ctx, uuid.New(), "@lite_other", domain.Phone{Number: first.Phone}, 0)
+	if !errors.Is(err, domain.ErrPhoneAlreadyExists) {
+		t.Fatalf("expected ErrPhoneAlreadyExists, got %v", err)
+	}
+
+	_, err = repo.Create(ctx, uuid.New(), first.Nick, domain.Phone{Number: "+15550000203"}, 0)
+	if !errors.Is(err, domain.ErrNickAlreadyExists) {
+		t.Fatalf("expected ErrNickAlreadyExists on create, got %v", err)
+	}
+
+	second, err := repo.Create(ctx, uuid.New(), "@lite_free", domain.Phone{Number: "+15550000204"}, 0)
+	if err != nil {
+		t.Fatalf("second Create failed: %v", err)
+	}
+
+	_, err = repo.UpdateNick(ctx, second.ID, first.Nick, 0)
+	if !errors.Is(err, domain.ErrNickAlreadyExists) {
+		t.Fatalf("expected ErrNickAlreadyExists on update, got %v", err)
+	}
+}
+
+func TestSQLiteStatusTransitions(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	ctx := context.Background()
+
+	acc, err := repo.Create(ctx, uuid.New(), "@lite_status", domain.Phone{Number: "+15550000301"}, 0)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if acc.Status != domain.StatusActive {
+		t.Fatalf("expected new account to be active, got %q", acc.Status)
+	}
+
+	suspended, err := repo.UpdateStatus(ctx, acc.ID, domain.StatusSuspended, domain.StatusReasonAbuse, domain.StatusActive)
+	if err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	if suspended.Status != domain.StatusSuspended || suspended.StatusReason != domain.StatusReasonAbuse {
+		t.Fatalf("unexpected account after suspend: %+v", suspended)
+	}
+
+	_, err = repo.UpdateStatus(ctx, acc.ID, domain.StatusSuspended, domain.StatusReasonAbuse, domain.StatusActive)
+	if !errors.Is(err, domain.ErrInvalidStatusTransition) {
+		t.Fatalf("expected ErrInvalidStatusTransition, got %v", err)
+	}
+
+	_, err = repo.UpdateNick(ctx, acc.ID, "@lite_renamed", 0)
+	if !errors.Is(err, domain.ErrAccountNotActive) {
+		t.Fatalf("expected ErrAccountNotActive, got %v", err)
+	}
+
+	_, err = repo.UpdateStatus(ctx, uuid.New(), domain.StatusBanned, domain.StatusReasonFraud, domain.StatusActive)
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Fatalf("expected ErrAccountNotFound, got %v", err)
+	}
+
+	if err = repo.Delete(ctx, acc.ID); err != nil {
+		t.Fatalf("Delete of suspended account failed: %v", err)
+	}
+}
+
+func TestSQLiteUpdateProfile(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	ctx := context.Background()
+
+	acc, err := repo.Create(ctx, uuid.New(), "@lite_profile", domain.Phone{Number: "+15550000401"}, 0)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	displayName, locale := "Lite", "cs-CZ"
+	updated, err := repo.UpdateProfile(ctx, acc.ID, domain.ProfileUpdate{DisplayName: &displayName, Locale: &locale})
+	if err != nil {
+		t.Fatalf("UpdateProfile failed: %v", err)
+	}
+	if updated.Profile != (domain.Profile{DisplayName: displayName, Locale: locale}) {
+		t.Fatalf("unexpected profile after update: %+v", updated.Profile)
+	}
+
+	cleared := ""
+	updated, err = repo.UpdateProfile(ctx, acc.ID, domain.ProfileUpdate{DisplayName: &cleared})
+	if err != nil {
+		t.Fatalf("UpdateProfile failed: %v", err)
+	}
+	if updated.Profile != (domain.Profile{Locale: locale}) {
+		t.Fatalf("expected only display name to be cleared, got %+v", updated.Profile)
+	}
+
+	if _, err = repo.UpdateStatus(ctx, acc.ID, domain.StatusSuspended, domain.StatusReasonSpam, domain.StatusActive); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	_, err = repo.UpdateProfile(ctx, acc.ID, domain.ProfileUpdate{DisplayName: &displayName})
+	if !errors.Is(err, domain.ErrAccountNotActive) {
+		t.Fatalf("expected ErrAccountNotActive, got %v", err)
+	}
+}
+
+func TestSQLiteNickHistory(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	ctx := context.Background()
+	const cooldown = time.Hour
+
+	owner, err := repo.Create(ctx, uuid.New(), "@lite_old", domain.Phone{Number: "+15550000601"}, cooldown)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err = repo.UpdateNick(ctx, owner.ID, "@lite_new", cooldown); err != nil {
+		t.Fatalf("UpdateNick failed: %v", err)
+	}
+
+	got, err := repo.GetByNick(ctx, "@lite_old", time.Hour)
+	if err != nil || got.ID != owner.ID || got.Nick != "@lite_new" {
+		t.Fatalf("expected old nick to redirect to %s, got %+v, %v", owner.ID, got, err)
+	}
+	if _, err = repo.GetByNick(ctx, "@lite_old", 0); !errors.Is(err, domain.ErrAccountNotFound) {
+		t.Fatalf("expected expired redirect to be not found, got %v", err)
+	}
+
+	other, err := repo.Create(ctx, uuid.New(), "@lite_other", domain.Phone{Number: "+15550000602"}, cooldown)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err = repo.UpdateNick(ctx, other.ID, "@lite_old", cooldown); !errors.Is(err, domain.ErrNickInCooldown) {
+		t.Fatalf("expected ErrNickInCooldown for another account, got %v", err)
+	}
+	if _, err = repo.Create(ctx, uuid.New(), "@lite_old", domain.Phone{Number: "+15550000603"}, cooldown); !errors.Is(err, domain.ErrNickInCooldown) {
+		t.Fatalf("expected ErrNickInCooldown on create, got %v", err)
+	}
+	if _, err = repo.UpdateNick(ctx, other.ID, "@lite_old", 0); err != nil {
+		t.Fatalf("expected nick to be claimable without cooldown, got %v", err)
+	}
+
+	got, err = repo.GetByNick(ctx, "@lite_old", time.Hour)
+	if err != nil || got.ID != other.ID {
+		t.Fatalf("expected current holder to win over redirect, got %+v, %v", got, err)
+	}
+
+	if _, err = repo.UpdateNick(ctx, owner.ID, "@lite_newest", cooldown); err != nil {
+		t.Fatalf("UpdateNick failed: %v", err)
+	}
+	if _, err = repo.UpdateNick(ctx, owner.ID, "@lite_new", cooldown); err != nil {
+		t.Fatalf("expected owner to reclaim its own released nick, got %v", err)
+	}
+
+	history, err := repo.ListNickHistory(ctx, owner.ID)
+	if err != nil {
+		t.Fatalf("ListNickHistory failed: %v", err)
+	}
+	var nicks []string
+	for _, c := range history {
+		nicks = append(nicks, c.Nick)
+	}
+	if want := []string{"@lite_newest", "@lite_new", "@lite_old"}; !slices.Equal(nicks, want) {
+		t.Fatalf("expected history %v, got %v", want, nicks)
+	}
+}
+
+func TestSQLiteNicksIgnoreCase(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	ctx := context.Background()
+	const cooldown = time.Hour
+
+	alice, err := repo.Create(ctx, uuid.New(), "@Alice", domain.Phone{Number: "+15550000701"}, cooldown)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err = repo.Create(ctx, uuid.New(), "@alice", domain.Phone{Number: "+15550000702"}, cooldown); !errors.Is(err, domain.ErrNickAlreadyExists) {
+		t.Fatalf("expected ErrNickAlreadyExists for nick differing in case, got %v", err)
+	}
+
+	got, err := repo.GetByNick(ctx, "@ALICE", 0)
+	if err != nil || got.ID != alice.ID || got.Nick != "@Alice" {
+		t.Fatalf("expected case-insensitive lookup to return @Alice, got %+v, %v", got, err)
+	}
+
+	bob, err := repo.Create(ctx, uuid.New(), "@bob", domain.Phone{Number: "+15550000703"}, cooldown)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err = repo.UpdateNick(ctx, bob.ID, "@aLiCe", cooldown); !errors.Is(err, domain.ErrNickAlreadyExists) {
+		t.Fatalf("expected ErrNickAlreadyExists on update, got %v", err)
+	}
+
+	renamed, err := repo.UpdateNick(ctx, alice.ID, "@ALICE", cooldown)
+	if err != nil || renamed.Nick != "@ALICE" {
+		t.Fatalf("expected owner to change the case of its nick, got %+v, %v", renamed, err)
+	}
+	history, err := repo.ListNickHistory(ctx, alice.ID)
+	if err != nil || len(history) != 0 {
+		t.Fatalf("expected a change of case not to be recorded, got %+v, %v", history, err)
+	}
+
+	if _, err = repo.UpdateNick(ctx, alice.ID, "@alice_new", cooldown); err != nil {
+		t.Fatalf("UpdateNick failed: %v", err)
+	}
+	if _, err = repo.UpdateNick(ctx, bob.ID, "@alice", cooldown); !errors.Is(err, domain.ErrNickInCooldown) {
+		t.Fatalf("expected cooldown to ignore case, got %v", err)
+	}
+	got, err = repo.GetByNick(ctx, "@alice", time.Hour)
+	if err != nil || got.ID != alice.ID {
+		t.Fatalf("expected redirect to ignore case, got %+v, %v", got, err)
+	}
+}
+
+func TestSQLiteCheckNicks(t *testing.T) {
+	repo := newSQLiteRepo(t)
+	ctx := context.Background()
+	const cooldown = time.Hour
+
+	holder, err := repo.Create(ctx, uuid.New(), "@Check_Old", domain.Phone{Number: "+15550000901"}, cooldown)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err = repo.UpdateNick(ctx, holder.ID, "@check_held", cooldown); err != nil {
+		t.Fatalf("UpdateNick failed: %v", err)
+	}
+
+	nicks := []string{"@CHECK_HELD", "@check_old", "@check_free"}
+	got, err := repo.CheckNicks(ctx, uuid.Nil, nicks, cooldown)
+	if err != nil {
+		t.Fatalf("CheckNicks failed: %v", err)
+	}
+	want := map[string]domain.NickCheckReason{"@check_held": domain.NickCheckTaken, "@check_old": domain.NickCheckCooldown}
+	if !maps.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got, err = repo.CheckNicks(ctx, holder.ID, nicks, cooldown)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("expected the holder to be able to claim its own nicks, got %v, %v", got, err)
+	}
+	got, err = repo.CheckNicks(ctx, uuid.Nil, nicks, 0)
+	if err != nil || !maps.Equal(got, map[string]domain.NickCheckReason{"@check_held": domain.NickCheckTaken}) {
+		t.Fatalf("expected only the held nick without cooldown, got %v, %v", got, err)
+	}
+}
+
+func TestSQLiteMigrationReportsNickCaseCollisions(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "account.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err = sqlite.Migrate(ctx, db); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+
+	// Roll back to before case-insensitive nicks and add colliding accounts.
+	rollback := `
+		DROP INDEX accounts_nick_key_key;
+		ALTER TABLE accounts DROP COLUMN nick_key;
+		DELETE FROM schema_migrations WHERE version = '20261018170000';
+	`
+	if _, err = db.ExecContext(ctx, rollback); err != nil {
+		t.Fatalf("roll back migration: %v", err)
+	}
+	repo := sqlite.New(db)
+	for nick, phone := range map[string]string{"@Alice": "+15550000801", "@alice": "+15550000802", "@bob": "+15550000803"} {
+		if _, err = repo.Create(ctx, uuid.New(), nick, domain.Phone{Number: phone}, 0); err != nil {
+			t.Fatalf("Create %s failed: %v", nick, err)
+		}
+	}
+
+	err = sqlite.Migrate(ctx, db)
+	if err == nil || !strings.Contains(err.Error(), "@Alice") || !strings.Contains(err.Error(), "@alice") || strings.Contains(err.Error(), "@bob") {
+		t.Fatalf("expected migration to report the @alice collision, got %v", err)
+	}
+
+	if _, err = db.ExecContext(ctx, `UPDATE accounts SET nick = '@alice_2' WHERE nick = '@alice'`); err != nil {
+		t.Fatalf("resolve collision: %v", err)
+	}
+	if err = sqlite.Migrate(ctx, db); err != nil {
+		t.Fatalf("expected migration to succeed once resolved, got %v", err)
+	}
+}
+
+func TestSQLiteOpenEscapesPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a?b#c%20d", "account.db")
+	if err := os.Mkdir(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	db, err := sqlite.Open(path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = sqlite.Migrate(ctx, db); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+
+	if _, err = os.Stat(path); err != nil {
+		t.Fatalf("expected the database at %s: %v", path, err)
+	}
+	var mode string
+	if err = db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode); err != nil || mode != "wal" {
+		t.Fatalf("expected the pragmas to apply, got journal_mode %q, %v", mode, err)
+	}
+}
@@ -0,0 +1,222 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+func TestMemoryVelocityStoreWindows(t *testing.T) {
+	ctx := context.Background()
+	store := accountsvc.NewMemoryVelocityStore()
+	window := time.Minute
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	for i := 1; i <= 3; i++ {
+		count, _ := store.Hit(ctx, "k", window, start.Add(time.Duration(i)*time.Second))
+		if count.Current != int64(i) || count.Previous != 0 || !count.WindowStart.Equal(start) {
+			t.Fatalf("unexpected count after %d hits: %+v", i, count)
+		}
+	}
+
+	count, _ := store.Hit(ctx, "k", window, start.Add(window+time.Second))
+	if count.Current != 1 || count.Previous != 3 {
+		t.Fatalf("unexpected count in the next window: %+v", count)
+	}
+
+	// After a window without hits, nothing is carried over.
+	count, _ = store.Hit(ctx, "k", window, start.Add(3*window))
+	if count.Current != 1 || count.Previous != 0 {
+		t.Fatalf("unexpected count after an idle window: %+v", count)
+	}
+
+	if count, _ = store.Hit(ctx, "other", window, start.Add(3*window)); count.Current != 1 {
+		t.Fatalf("expected keys to be counted separately, got %+v", count)
+	}
+}
+
+func TestCreateSignupVelocityPhonePrefix(t *testing.T) {
+	ctx := context.Background()
+	svc := accountsvc.New(newSQLiteRepo(t), accountsvc.WithSignupVelocity(accountsvc.NewMemoryVelocityStore(), accountsvc.SignupVelocityConfig{
+		PhonePrefix: accountsvc.VelocityLimit{Limit: 2, Window: time.Hour},
+	}))
+
+	for i := 1; i <= 2; i++ {
+		if _, err := svc.Create(ctx, domain.NewAccount{Phone: fmt.Sprintf("+1555000100%d", i)}); err != nil {
+			t.Fatalf("Create %d failed: %v", i, err)
+		}
+	}
+
+	_, err := svc.Create(ctx, domain.NewAccount{Phone: "+15550001003"})
+	var limited *domain.RateLimitedError
+	if !errors.Is(err, domain.ErrRateLimited) || !errors.As(err, &limited) {
+		t.Fatalf("expected the third number of the range to be rate limited, got %v", err)
+	}
+	if limited.Scope != accountsvc.VelocityScopePhonePrefix || limited.RetryAfter <= 0 || limited.RetryAfter > 2*time.Hour {
+		t.Fatalf("unexpected rate limit error: %+v", limited)
+	}
+
+	// Another thousand-number block is not affected.
+	if _, err = svc.Create(ctx, domain.NewAccount{Phone: "+15550002001"}); err != nil {
+		t.Fatalf("expected another range to be allowed, got %v", err)
+	}
+}
+
+func TestCreateSignupVelocityIP(t *testing.T) {
+	svc := accountsvc.New(newSQLiteRepo(t), accountsvc.WithSignupVelocity(accountsvc.NewMemoryVelocityStore(), accountsvc.SignupVelocityConfig{
+		IP: accountsvc.VelocityLimit{Limit: 1, Window: time.Hour},
+	}))
+
+	create := func(ip, phone string) error {
+		ctx := domain.WithCaller(context.Background(), domain.Caller{IP: ip})
+		_, err := svc.Create(ctx, domain.NewAccount{Phone: phone})
+		return err
+	}
+
+	if err := create("2001:db8::1", "+15550003001"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// Addresses in one IPv6 /64 share a limit.
+	if err := create("2001:db8::2", "+15550004001"); !errors.Is(err, domain.ErrRateLimited) {
+		t.Fatalf("expected the same /64 to be rate limited, got %v", err)
+	}
+	if err := create("192.0.2.1", "+15550005001"); err != nil {
+		t.Fatalf("expected another IP to be allowed, got %v", err)
+	}
+}
+
+// keyRecordingVelocityStore records the keys of its hits.
+type keyRecordingVelocityStore struct {
+	accountsvc.VelocityStore
+	keys []string
+}
+
+func (s *keyRecordingVelocityStore) Hit(ctx context.Context, key string, window time.Duration, now time.Time) (accountsvc.VelocityCount, error) {
+	s.keys = append(s.keys, key)
+	return s.VelocityStore.Hit(ctx, key, window, now)
+}
+
+func TestCreateSignupVelocityLongCallerID(t *testing.T) {
+	store := &keyRecordingVelocityStore{VelocityStore: accountsvc.NewMemoryVelocityStore()}
+	svc := accountsvc.New(newSQLiteRepo(t), accountsvc.WithSignupVelocity(store, accountsvc.SignupVelocityConfig{
+		Caller: accountsvc.VelocityLimit{Limit: 1, Window: time.Hour},
+	}))
+
+	create := func(id, phone string) error {
+		ctx := domain.WithCaller(context.Background(), domain.Caller{ID: id})
+		_, err := svc.Create(ctx, domain.NewAccount{Phone: phone})
+		return err
+	}
+
+	long := "spiffe://example.org/" + strings.Repeat("a", 300)
+	if err := create(long, "+15550009001"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if len(store.keys) != 1 || len(store.keys[0]) > 128 {
+		t.Fatalf("expected a key within 128 bytes, got %q", store.keys)
+	}
+	if err := create(long, "+15550010001"); !errors.Is(err, domain.ErrRateLimited) {
+		t.Fatalf("expected the same long caller to be rate limited, got %v", err)
+	}
+	if err := create(long+"b", "+15550011001"); err != nil {
+		t.Fatalf("expected another long caller to be allowed, got %v", err)
+	}
+}
+
+func TestCreateAccountGRPCRateLimitDetails(t *testing.T) {
+	client := startGRPCClient(t, newSQLiteRepo(t), accountsvc.WithSignupVelocity(accountsvc.NewMemoryVelocityStore(), accountsvc.SignupVelocityConfig{
+		Caller: accountsvc.VelocityLimit{Limit: 1, Window: time.Hour},
+	}))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), grpcapi.CallerIDHeader, "signup-bff")
+
+	if _, err := client.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: "+15550006001"}); err != nil {
+		t.Fatalf("CreateAccount failed: %v", err)
+	}
+	// Callers without an identity are not limited by it.
+	if _, err := client.CreateAccount(context.Background(), &accountv1.CreateAccountRequest{Phone: "+15550007001"}); err != nil {
+		t.Fatalf("CreateAccount without caller id failed: %v", err)
+	}
+
+	_, err := client.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: "+15550008001"})
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", st.Code())
+	}
+
+	var info *errdetails.ErrorInfo
+	var retry *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.RetryInfo:
+			retry = d
+		}
+	}
+	if info == nil || info.GetReason() != grpcapi.ReasonRateLimited || info.GetMetadata()["scope"] != accountsvc.VelocityScopeCaller {
+		t.Fatalf("unexpected ErrorInfo: %v", info)
+	}
+	if retry == nil || retry.GetRetryDelay().AsDuration() <= 0 {
+		t.Fatalf("expected a positive RetryInfo delay, got %v", retry)
+	}
+}
+
+func TestCreateAccountGRPCForwardedForFromTrustedProxy(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	svc := accountsvc.New(newSQLiteRepo(t), accountsvc.WithSignupVelocity(accountsvc.NewMemoryVelocityStore(), accountsvc.SignupVelocityConfig{
+		IP: accountsvc.VelocityLimit{Limit: 1, Window: time.Hour},
+	}))
+	s := grpc.NewServer()
+	accountv1.RegisterAccountServiceServer(s, grpcapi.NewServer(svc, slog.Default(),
+		grpcapi.WithTrustedProxies([]netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")})))
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	client := accountv1.NewAccountServiceClient(conn)
+
+	create := func(forwardedFor, phone string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), grpcapi.ForwardedForHeader, forwardedFor)
+		_, err := client.CreateAccount(ctx, &accountv1.CreateAccountRequest{Phone: phone})
+		return err
+	}
+
+	if err = create("198.51.100.7", "+15550009001"); err != nil {
+		t.Fatalf("CreateAccount failed: %v", err)
+	}
+	// Addresses added by trusted proxies are skipped.
+	if err = create("198.51.100.7, 127.0.0.2", "+15550010001"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected the forwarded client to be rate limited, got %v", err)
+	}
+	if err = create("198.51.100.8", "+15550011001"); err != nil {
+		t.Fatalf("expected another forwarded client to be allowed, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/accounttest"
+	"github.com/kvetinski/account/client"
+	"github.com/kvetinski/account/internal/adapters/grpcapi/accountv1"
+)
+
+func TestAccountTestSeedAccount(t *testing.T) {
+	srv := accounttest.New(t)
+	ctx := context.Background()
+
+	seeded := srv.SeedAccount(accounttest.Seed{
+		Nick:        "@seeded",
+		DisplayName: "Seeded User",
+		TimeZone:    "Europe/Berlin",
+		Status:      client.AccountStatus_ACCOUNT_STATUS_SUSPENDED,
+	})
+	if seeded.GetNick() != "@seeded" || seeded.GetStatus() != client.AccountStatus_ACCOUNT_STATUS_SUSPENDED {
+		t.Fatalf("unexpected seeded account: %v", seeded)
+	}
+
+	resp, err := srv.Client().GetAccount(ctx, &client.GetAccountRequest{Id: seeded.GetId()})
+	if err != nil {
+		t.Fatalf("GetAccount failed: %v", err)
+	}
+	if p := resp.GetAccount().GetProfile(); p.GetDisplayName() != "Seeded User" || p.GetTimeZone() != "Europe/Berlin" {
+		t.Fatalf("unexpected profile: %v", p)
+	}
+
+	// Seeds without a phone get distinct numbers.
+	a, b := srv.SeedAccount(accounttest.Seed{}), srv.SeedAccount(accounttest.Seed{})
+	if a.GetPhone() == b.GetPhone() || a.GetNick() == "" {
+		t.Fatalf("expected distinct generated accounts, got %v and %v", a, b)
+	}
+}
+
+func TestAccountTestInjectError(t *testing.T) {
+	srv := accounttest.New(t)
+	ctx := context.Background()
+	acc := srv.SeedAccount(accounttest.Seed{})
+
+	srv.Inject("GetAccount", accounttest.Fault{Err: client.ErrAccountNotFound, Times: 1})
+
+	_, err := srv.Client().GetAccount(ctx, &client.GetAccountRequest{Id: acc.GetId()})
+	if !errors.Is(err, client.ErrAccountNotFound) || status.Code(err) != codes.NotFound {
+		t.Fatalf("expected the injected ErrAccountNotFound, got %v", err)
+	}
+	if _, err = srv.Client().GetAccount(ctx, &client.GetAccountRequest{Id: acc.GetId()}); err != nil {
+		t.Fatalf("expected the fault to apply once, got %v", err)
+	}
+	if got := srv.Calls("GetAccount"); got != 2 {
+		t.Fatalf("expected 2 calls, got %d", got)
+	}
+
+	srv.Inject(accounttest.AllMethods, accounttest.Fault{Err: status.Error(codes.PermissionDenied, "denied")})
+	if _, err = srv.Client().CheckNick(ctx, &client.CheckNickRequest{Nick: "@any"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for every method, got %v", err)
+	}
+
+	srv.Reset()
+	if _, err = srv.Client().CheckNick(ctx, &client.CheckNickRequest{Nick: "@any"}); err != nil {
+		t.Fatalf("expected no faults after Reset, got %v", err)
+	}
+}
+
+func TestAccountTestInjectLatency(t *testing.T) {
+	srv := accounttest.New(t, accounttest.WithClientOptions(client.WithTimeout(50*time.Millisecond)))
+	srv.Inject("CheckNick", accounttest.Fault{Latency: time.Hour})
+
+	_, err := srv.Client().CheckNick(context.Background(), &client.CheckNickRequest{Nick: "@slow"})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestAccountTestClientRetriesInjectedUnavailable(t *testing.T) {
+	srv := accounttest.New(t)
+	acc := srv.SeedAccount(accounttest.Seed{})
+	srv.Inject("GetAccount", accounttest.Fault{Err: status.Error(codes.Unavailable, "overloaded"), Times: 2})
+
+	if _, err := srv.Client().GetAccount(context.Background(), &client.GetAccountRequest{Id: acc.GetId()}); err != nil {
+		t.Fatalf("expected the client to retry, got %v", err)
+	}
+	if got := srv.Calls("GetAccount"); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestAccountTestDial(t *testing.T) {
+	srv := accounttest.New(t)
+	acc := srv.SeedAccount(accounttest.Seed{Nick: "@dialed"})
+
+	conn, err := grpc.NewClient(accounttest.Target,
+		grpc.WithContextDialer(srv.Dial),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	resp, err := accountv1.NewAccountServiceClient(conn).GetAccountByNick(context.Background(), &accountv1.GetAccountByNickRequest{Nick: "@dialed"})
+	if err != nil || resp.GetAccount().GetId() != acc.GetId() {
+		t.Fatalf("expected the seeded account, got %v, %v", resp, err)
+	}
+}
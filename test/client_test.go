@@ -0,0 +1,176 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kvetinski/account/client"
+	"github.com/kvetinski/account/internal/adapters/grpcapi"
+	"github.com/kvetinski/account/internal/domain"
+	accountsvc "github.com/kvetinski/account/internal/service/account"
+)
+
+// startSDKClient serves svc over bufconn with serverOpts and returns an SDK
+// client for it.
+func startSDKClient(t *testing.T, svc *accountsvc.Service, serverOpts []grpc.ServerOption, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	listener := serveGRPC(t, svc, serverOpts...)
+	opts = append(opts, client.WithDialOptions(grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	})))
+
+	c, err := client.New("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("client.New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
+
+// failFirst fails the first n calls of each method with code.
+func failFirst(n int, code codes.Code) (grpc.ServerOption, func(method string) int) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+
+	interceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		mu.Lock()
+		calls[info.FullMethod]++
+		call := calls[info.FullMethod]
+		mu.Unlock()
+
+		if call <= n {
+			return nil, status.Error(code, "try again")
+		}
+		return handler(ctx, req)
+	}
+	count := func(method string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls[method]
+	}
+
+	return grpc.ChainUnaryInterceptor(interceptor), count
+}
+
+func TestClientTypedErrors(t *testing.T) {
+	c := startSDKClient(t, accountsvc.New(grpcRepoStub{err: domain.ErrAccountNotFound}), nil)
+
+	_, err := c.GetAccount(context.Background(), &client.GetAccountRequest{Id: uuid.New().String()})
+	if !errors.Is(err, client.ErrAccountNotFound) {
+		t.Fatalf("expected ErrAccountNotFound, got %v", err)
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected the status code to be kept, got %v", status.Code(err))
+	}
+	var clientErr *client.Error
+	if !errors.As(err, &clientErr) {
+		t.Fatalf("expected a *client.Error, got %T", err)
+	}
+}
+
+func TestClientTypedFilterError(t *testing.T) {
+	c := startSDKClient(t, accountsvc.New(grpcRepoStub{}, accountsvc.WithNickFilter(testWordlistFilter())), nil)
+
+	_, err := c.UpdateNick(context.Background(), &client.UpdateNickRequest{Id: uuid.New().String(), Nick: "@f_u_c_k"})
+	var notAllowed *client.NickNotAllowedError
+	if !errors.As(err, &notAllowed) || notAllowed.Category != "profanity" {
+		t.Fatalf("expected NickNotAllowedError, got %v", err)
+	}
+}
+
+func TestClientStreamErrors(t *testing.T) {
+	c := startSDKClient(t, accountsvc.New(grpcRepoStub{}), nil)
+
+	stream, err := c.UploadAvatar(context.Background())
+	if err != nil {
+		t.Fatalf("UploadAvatar failed: %v", err)
+	}
+	_ = stream.Send(&client.UploadAvatarRequest{Payload: &client.UploadAvatarRequest_Id{Id: uuid.New().String()}})
+	if _, err = stream.CloseAndRecv(); !errors.Is(err, client.ErrAvatarUploadDisabled) {
+		t.Fatalf("expected ErrAvatarUploadDisabled, got %v", err)
+	}
+}
+
+func TestClientRetriesIdempotentCalls(t *testing.T) {
+	failing, calls := failFirst(2, codes.Unavailable)
+	c := startSDKClient(t, accountsvc.New(newSQLiteRepo(t)), []grpc.ServerOption{failing},
+		client.WithRetryPolicy(client.RetryPolicy{
+			MaxAttempts:       3,
+			InitialBackoff:    time.Millisecond,
+			MaxBackoff:        time.Millisecond,
+			BackoffMultiplier: 1,
+		}))
+	ctx := context.Background()
+
+	if _, err := c.CheckNick(ctx, &client.CheckNickRequest{Nick: "@retry"}); err != nil {
+		t.Fatalf("expected CheckNick to succeed after retries, got %v", err)
+	}
+	if got := calls("/account.v1.AccountService/CheckNick"); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+
+	_, err := c.CreateAccount(ctx, &client.CreateAccountRequest{Phone: "+15550013001"})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected CreateAccount to fail with Unavailable, got %v", err)
+	}
+	if got := calls("/account.v1.AccountService/CreateAccount"); got != 1 {
+		t.Fatalf("expected CreateAccount not to be retried, got %d attempts", got)
+	}
+}
+
+func TestClientDefaultTimeout(t *testing.T) {
+	slow := grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	c := startSDKClient(t, accountsvc.New(newSQLiteRepo(t)), []grpc.ServerOption{slow}, client.WithTimeout(50*time.Millisecond))
+
+	_, err := c.CheckNick(context.Background(), &client.CheckNickRequest{Nick: "@slow"})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestClientCallerIDAndTracing(t *testing.T) {
+	var callerID string
+	capture := grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if v := md.Get(grpcapi.CallerIDHeader); len(v) > 0 {
+			callerID = v[0]
+		}
+		return handler(ctx, req)
+	})
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	c := startSDKClient(t, accountsvc.New(newSQLiteRepo(t)), []grpc.ServerOption{capture},
+		client.WithCallerID("signup-bff"),
+		client.WithTelemetryOptions(otelgrpc.WithTracerProvider(tp)))
+
+	if _, err := c.CheckNick(context.Background(), &client.CheckNickRequest{Nick: "@traced"}); err != nil {
+		t.Fatalf("CheckNick failed: %v", err)
+	}
+	if callerID != "signup-bff" {
+		t.Fatalf("expected the caller id header, got %q", callerID)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "account.v1.AccountService/CheckNick" {
+		t.Fatalf("expected one client span, got %v", spans)
+	}
+}